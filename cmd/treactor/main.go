@@ -1,12 +1,16 @@
 package main
 
 import (
+	"log"
+
 	"github.com/treactor/treactor-go/pkg/resource"
 	"github.com/treactor/treactor-go/pkg/treact"
 )
 
 func main() {
 	resource.Configure()
-	resource.Init()
+	if err := resource.Init(); err != nil {
+		log.Fatal(err)
+	}
 	treact.Serve()
 }
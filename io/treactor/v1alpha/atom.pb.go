@@ -25,22 +25,118 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type Quantity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value       float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	Uncertainty float64 `protobuf:"fixed64,2,opt,name=uncertainty,proto3" json:"uncertainty,omitempty"`
+	Estimated   bool    `protobuf:"varint,3,opt,name=estimated,proto3" json:"estimated,omitempty"`
+	MassNumber  bool    `protobuf:"varint,4,opt,name=mass_number,json=massNumber,proto3" json:"mass_number,omitempty"`
+	Approximate bool    `protobuf:"varint,5,opt,name=approximate,proto3" json:"approximate,omitempty"`
+	UpperBound  bool    `protobuf:"varint,6,opt,name=upper_bound,json=upperBound,proto3" json:"upper_bound,omitempty"`
+}
+
+func (x *Quantity) Reset() {
+	*x = Quantity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_io_treactor_v1alpha_atom_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Quantity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quantity) ProtoMessage() {}
+
+func (x *Quantity) ProtoReflect() protoreflect.Message {
+	mi := &file_io_treactor_v1alpha_atom_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quantity.ProtoReflect.Descriptor instead.
+func (*Quantity) Descriptor() ([]byte, []int) {
+	return file_io_treactor_v1alpha_atom_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Quantity) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Quantity) GetUncertainty() float64 {
+	if x != nil {
+		return x.Uncertainty
+	}
+	return 0
+}
+
+func (x *Quantity) GetEstimated() bool {
+	if x != nil {
+		return x.Estimated
+	}
+	return false
+}
+
+func (x *Quantity) GetMassNumber() bool {
+	if x != nil {
+		return x.MassNumber
+	}
+	return false
+}
+
+func (x *Quantity) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+func (x *Quantity) GetUpperBound() bool {
+	if x != nil {
+		return x.UpperBound
+	}
+	return false
+}
+
 type Atom struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Number int32  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
-	Symbol string `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Name   string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Period *int32 `protobuf:"varint,4,opt,name=period,proto3,oneof" json:"period,omitempty"`
-	Group  *int32 `protobuf:"varint,5,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Number            int32     `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Symbol            string    `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Name              string    `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Period            *int32    `protobuf:"varint,4,opt,name=period,proto3,oneof" json:"period,omitempty"`
+	Group             *int32    `protobuf:"varint,5,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Origin            string    `protobuf:"bytes,6,opt,name=origin,proto3" json:"origin,omitempty"`
+	Category          string    `protobuf:"bytes,7,opt,name=category,proto3" json:"category,omitempty"`
+	Weight            *Quantity `protobuf:"bytes,8,opt,name=weight,proto3" json:"weight,omitempty"`
+	Density           *Quantity `protobuf:"bytes,9,opt,name=density,proto3" json:"density,omitempty"`
+	Melt              *Quantity `protobuf:"bytes,10,opt,name=melt,proto3" json:"melt,omitempty"`
+	Boil              *Quantity `protobuf:"bytes,11,opt,name=boil,proto3" json:"boil,omitempty"`
+	HeatCapacity      *Quantity `protobuf:"bytes,12,opt,name=heat_capacity,json=heatCapacity,proto3" json:"heat_capacity,omitempty"`
+	Electronegativity *Quantity `protobuf:"bytes,13,opt,name=electronegativity,proto3" json:"electronegativity,omitempty"`
+	Abundance         *Quantity `protobuf:"bytes,14,opt,name=abundance,proto3" json:"abundance,omitempty"`
 }
 
 func (x *Atom) Reset() {
 	*x = Atom{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_io_treactor_v1alpha_atom_proto_msgTypes[0]
+		mi := &file_io_treactor_v1alpha_atom_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -53,7 +149,7 @@ func (x *Atom) String() string {
 func (*Atom) ProtoMessage() {}
 
 func (x *Atom) ProtoReflect() protoreflect.Message {
-	mi := &file_io_treactor_v1alpha_atom_proto_msgTypes[0]
+	mi := &file_io_treactor_v1alpha_atom_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -66,7 +162,7 @@ func (x *Atom) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Atom.ProtoReflect.Descriptor instead.
 func (*Atom) Descriptor() ([]byte, []int) {
-	return file_io_treactor_v1alpha_atom_proto_rawDescGZIP(), []int{0}
+	return file_io_treactor_v1alpha_atom_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Atom) GetNumber() int32 {
@@ -104,25 +200,121 @@ func (x *Atom) GetGroup() int32 {
 	return 0
 }
 
+func (x *Atom) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *Atom) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Atom) GetWeight() *Quantity {
+	if x != nil {
+		return x.Weight
+	}
+	return nil
+}
+
+func (x *Atom) GetDensity() *Quantity {
+	if x != nil {
+		return x.Density
+	}
+	return nil
+}
+
+func (x *Atom) GetMelt() *Quantity {
+	if x != nil {
+		return x.Melt
+	}
+	return nil
+}
+
+func (x *Atom) GetBoil() *Quantity {
+	if x != nil {
+		return x.Boil
+	}
+	return nil
+}
+
+func (x *Atom) GetHeatCapacity() *Quantity {
+	if x != nil {
+		return x.HeatCapacity
+	}
+	return nil
+}
+
+func (x *Atom) GetElectronegativity() *Quantity {
+	if x != nil {
+		return x.Electronegativity
+	}
+	return nil
+}
+
+func (x *Atom) GetAbundance() *Quantity {
+	if x != nil {
+		return x.Abundance
+	}
+	return nil
+}
+
 var File_io_treactor_v1alpha_atom_proto protoreflect.FileDescriptor
 
 var file_io_treactor_v1alpha_atom_proto_rawDesc = []byte{
 	0x0a, 0x1e, 0x69, 0x6f, 0x2f, 0x74, 0x72, 0x65, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x2f, 0x76, 0x31,
 	0x61, 0x6c, 0x70, 0x68, 0x61, 0x2f, 0x61, 0x74, 0x6f, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x22, 0x97, 0x01, 0x0a, 0x04, 0x41, 0x74, 0x6f, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d,
-	0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65,
-	0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a,
-	0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52,
-	0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x67, 0x72,
-	0x6f, 0x75, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x05, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64,
-	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x35, 0x0a, 0x13, 0x69, 0x6f,
-	0x2e, 0x74, 0x72, 0x65, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
-	0x61, 0x5a, 0x1e, 0x69, 0x6f, 0x2f, 0x74, 0x72, 0x65, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x2f, 0x76,
-	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x3b, 0x74, 0x72, 0x65, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x70,
-	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x22, 0xc4, 0x01, 0x0a, 0x08, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x74, 0x61, 0x69, 0x6e,
+	0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x74,
+	0x61, 0x69, 0x6e, 0x74, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x73, 0x73, 0x5f, 0x6e, 0x75, 0x6d, 0x62,
+	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x6d, 0x61, 0x73, 0x73, 0x4e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x6d,
+	0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x61, 0x70, 0x70, 0x72, 0x6f,
+	0x78, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x70, 0x70, 0x65, 0x72, 0x5f,
+	0x62, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x75, 0x70, 0x70,
+	0x65, 0x72, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x22, 0xe3, 0x03, 0x0a, 0x04, 0x41, 0x74, 0x6f, 0x6d,
+	0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62,
+	0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x19, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x01, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79,
+	0x12, 0x21, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x09, 0x2e, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x77, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x12, 0x23, 0x0a, 0x07, 0x64, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x79, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52,
+	0x07, 0x64, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x79, 0x12, 0x1d, 0x0a, 0x04, 0x6d, 0x65, 0x6c, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x52, 0x04, 0x6d, 0x65, 0x6c, 0x74, 0x12, 0x1d, 0x0a, 0x04, 0x62, 0x6f, 0x69, 0x6c, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x52, 0x04, 0x62, 0x6f, 0x69, 0x6c, 0x12, 0x2e, 0x0a, 0x0d, 0x68, 0x65, 0x61, 0x74, 0x5f, 0x63,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e,
+	0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x0c, 0x68, 0x65, 0x61, 0x74, 0x43, 0x61,
+	0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x37, 0x0a, 0x11, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x72,
+	0x6f, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x09, 0x2e, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x11, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x72, 0x6f, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x12,
+	0x27, 0x0a, 0x09, 0x61, 0x62, 0x75, 0x6e, 0x64, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x09, 0x2e, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x09, 0x61,
+	0x62, 0x75, 0x6e, 0x64, 0x61, 0x6e, 0x63, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70, 0x65, 0x72,
+	0x69, 0x6f, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x35, 0x0a,
+	0x13, 0x69, 0x6f, 0x2e, 0x74, 0x72, 0x65, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x5a, 0x1e, 0x69, 0x6f, 0x2f, 0x74, 0x72, 0x65, 0x61, 0x63, 0x74, 0x6f,
+	0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x3b, 0x74, 0x72, 0x65, 0x61, 0x63, 0x74,
+	0x6f, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -137,16 +329,24 @@ func file_io_treactor_v1alpha_atom_proto_rawDescGZIP() []byte {
 	return file_io_treactor_v1alpha_atom_proto_rawDescData
 }
 
-var file_io_treactor_v1alpha_atom_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_io_treactor_v1alpha_atom_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_io_treactor_v1alpha_atom_proto_goTypes = []interface{}{
-	(*Atom)(nil), // 0: Atom
+	(*Quantity)(nil), // 0: Quantity
+	(*Atom)(nil),     // 1: Atom
 }
 var file_io_treactor_v1alpha_atom_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: Atom.weight:type_name -> Quantity
+	0, // 1: Atom.density:type_name -> Quantity
+	0, // 2: Atom.melt:type_name -> Quantity
+	0, // 3: Atom.boil:type_name -> Quantity
+	0, // 4: Atom.heat_capacity:type_name -> Quantity
+	0, // 5: Atom.electronegativity:type_name -> Quantity
+	0, // 6: Atom.abundance:type_name -> Quantity
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_io_treactor_v1alpha_atom_proto_init() }
@@ -156,6 +356,18 @@ func file_io_treactor_v1alpha_atom_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_io_treactor_v1alpha_atom_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Quantity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_io_treactor_v1alpha_atom_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Atom); i {
 			case 0:
 				return &v.state
@@ -168,14 +380,14 @@ func file_io_treactor_v1alpha_atom_proto_init() {
 			}
 		}
 	}
-	file_io_treactor_v1alpha_atom_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	file_io_treactor_v1alpha_atom_proto_msgTypes[1].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_io_treactor_v1alpha_atom_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
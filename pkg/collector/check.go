@@ -0,0 +1,166 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/tracestore"
+)
+
+const (
+	IssueMissingClient = "missing_client_span"
+	IssueMissingServer = "missing_server_span"
+	IssueBrokenParent  = "broken_parent"
+	IssueDuplicate     = "duplicate_span"
+	IssueClockSkew     = "clock_skew"
+)
+
+type Issue struct {
+	Kind    string `json:"kind"`
+	Hop     string `json:"hop,omitempty"`
+	SpanID  string `json:"spanId,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report is the outcome of comparing the received spans with the response tree of a reaction
+type Report struct {
+	TraceID  string  `json:"traceId"`
+	Hops     int     `json:"hops"`
+	Spans    int     `json:"spans"`
+	Complete bool    `json:"complete"`
+	Issues   []Issue `json:"issues,omitempty"`
+}
+
+func (r *Report) add(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+}
+
+// hop is a call in the response tree, parentSpanID is the client span of the caller as it was
+// propagated in the traceparent header
+type hop struct {
+	path         string
+	parentSpanID string
+}
+
+// TraceParent extracts the trace and parent span id of the w3c traceparent request header of a node
+func TraceParent(node *treactorpb.Node) (traceID string, spanID string, ok bool) {
+	header := node.GetRequest().GetHeaders()["Traceparent"]
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func collectHops(node *treactorpb.Node, hops []hop) []hop {
+	if node == nil {
+		return hops
+	}
+	if _, spanID, ok := TraceParent(node); ok {
+		hops = append(hops, hop{path: node.GetRequest().GetPath(), parentSpanID: spanID})
+	}
+	for _, bond := range node.Bonds {
+		hops = collectHops(bond.Node, hops)
+	}
+	return hops
+}
+
+// Check compares the spans received for a trace with the response tree. It reports hops of which
+// the client or server span is missing, spans pointing to a parent that was never received,
+// spans received more than once and server spans that lie outside their client span by more
+// than the tolerated clock skew.
+func Check(traceID string, root *treactorpb.Node, spans []*tracestore.Span, tolerance time.Duration) *Report {
+	report := &Report{
+		TraceID: traceID,
+		Spans:   len(spans),
+	}
+
+	byID := make(map[string]*tracestore.Span, len(spans))
+	serverByParent := make(map[string]*tracestore.Span)
+	for _, span := range spans {
+		if _, seen := byID[span.SpanID]; seen {
+			report.add(Issue{
+				Kind:    IssueDuplicate,
+				SpanID:  span.SpanID,
+				Message: fmt.Sprintf("span %q received more than once", span.Name),
+			})
+			continue
+		}
+		byID[span.SpanID] = span
+		if span.Kind == "server" && span.ParentSpanID != "" {
+			serverByParent[span.ParentSpanID] = span
+		}
+	}
+
+	for _, span := range byID {
+		if span.ParentSpanID == "" {
+			continue
+		}
+		if _, ok := byID[span.ParentSpanID]; !ok {
+			report.add(Issue{
+				Kind:    IssueBrokenParent,
+				SpanID:  span.SpanID,
+				Message: fmt.Sprintf("parent %s of span %q was not received", span.ParentSpanID, span.Name),
+			})
+		}
+	}
+
+	hops := collectHops(root, nil)
+	report.Hops = len(hops)
+	for _, h := range hops {
+		client, hasClient := byID[h.parentSpanID]
+		if !hasClient {
+			report.add(Issue{
+				Kind:    IssueMissingClient,
+				Hop:     h.path,
+				SpanID:  h.parentSpanID,
+				Message: "client span of the caller was not received",
+			})
+		}
+		server, hasServer := serverByParent[h.parentSpanID]
+		if !hasServer {
+			report.add(Issue{
+				Kind:    IssueMissingServer,
+				Hop:     h.path,
+				SpanID:  h.parentSpanID,
+				Message: "no server span received with the client span as parent",
+			})
+		}
+		if !hasClient || !hasServer {
+			continue
+		}
+		before := client.StartTime.Sub(server.StartTime)
+		after := server.EndTime.Sub(client.EndTime)
+		if before > tolerance || after > tolerance {
+			report.add(Issue{
+				Kind:    IssueClockSkew,
+				Hop:     h.path,
+				SpanID:  server.SpanID,
+				Message: fmt.Sprintf("server span starts %s before and ends %s after its client span", before, after),
+			})
+		}
+	}
+
+	report.Complete = len(report.Issues) == 0
+	return report
+}
+
+// Received returns true when for every hop in the response tree both the client and server span arrived
+func Received(root *treactorpb.Node, spans []*tracestore.Span) bool {
+	ids := make(map[string]bool, len(spans))
+	parents := make(map[string]bool, len(spans))
+	for _, span := range spans {
+		ids[span.SpanID] = true
+		if span.Kind == "server" {
+			parents[span.ParentSpanID] = true
+		}
+	}
+	for _, h := range collectHops(root, nil) {
+		if !ids[h.parentSpanID] || !parents[h.parentSpanID] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/tracestore"
+)
+
+func node(path string, parentSpanID string, bonds ...*treactorpb.Node) *treactorpb.Node {
+	n := &treactorpb.Node{
+		Request: &treactorpb.TReactorRequest{
+			Path:    path,
+			Headers: map[string]string{"Traceparent": "00-t-" + parentSpanID + "-01"},
+		},
+	}
+	for _, b := range bonds {
+		n.Bonds = append(n.Bonds, &treactorpb.Bond{Node: b})
+	}
+	return n
+}
+
+func span(id string, parent string, kind string, start int, end int) *tracestore.Span {
+	base := time.Unix(0, 0)
+	return &tracestore.Span{
+		TraceID:      "t",
+		SpanID:       id,
+		ParentSpanID: parent,
+		Name:         id,
+		Kind:         kind,
+		StartTime:    base.Add(time.Duration(start) * time.Millisecond),
+		EndTime:      base.Add(time.Duration(end) * time.Millisecond),
+	}
+}
+
+func kinds(report *Report) []string {
+	var k []string
+	for _, issue := range report.Issues {
+		k = append(k, issue.Kind)
+	}
+	return k
+}
+
+func TestCheckComplete(t *testing.T) {
+	root := node("/reactions", "c1", node("/atoms/h", "c2"))
+	spans := []*tracestore.Span{
+		span("c1", "", "client", 0, 100),
+		span("s1", "c1", "server", 1, 99),
+		span("c2", "s1", "client", 10, 50),
+		span("s2", "c2", "server", 11, 49),
+	}
+
+	assert.True(t, Received(root, spans))
+	report := Check("t", root, spans, time.Millisecond)
+	assert.True(t, report.Complete)
+	assert.Equal(t, 2, report.Hops)
+}
+
+func TestCheckIssues(t *testing.T) {
+	root := node("/reactions", "c1", node("/atoms/h", "c2"), node("/atoms/o", "c3"))
+	spans := []*tracestore.Span{
+		span("c1", "", "client", 0, 100),
+		span("s1", "c1", "server", 1, 99),
+		span("c2", "s1", "client", 10, 50),
+		span("s2", "c2", "server", 5, 49),
+		span("s2", "c2", "server", 5, 49),
+		span("x", "gone", "internal", 20, 30),
+	}
+
+	assert.False(t, Received(root, spans))
+	report := Check("t", root, spans, time.Millisecond)
+	assert.False(t, report.Complete)
+	assert.ElementsMatch(t, []string{
+		IssueDuplicate,
+		IssueBrokenParent,
+		IssueClockSkew,
+		IssueMissingClient,
+		IssueMissingServer,
+	}, kinds(report))
+}
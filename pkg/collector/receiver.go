@@ -0,0 +1,172 @@
+package collector
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/treactor/treactor-go/pkg/tracestore"
+)
+
+// Receiver accepts OTLP spans over gRPC and HTTP and keeps them in the trace store
+type Receiver struct {
+	collectortrace.UnimplementedTraceServiceServer
+	store *tracestore.Store
+}
+
+func NewReceiver(store *tracestore.Store) *Receiver {
+	return &Receiver{store: store}
+}
+
+func (rc *Receiver) Export(_ context.Context, request *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	rc.store.Add(fromResourceSpans(request.ResourceSpans)...)
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+// ServeGRPC blocks while serving OTLP/gRPC on the port
+func (rc *Receiver) ServeGRPC(port string) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		return err
+	}
+	server := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(server, rc)
+	return server.Serve(listener)
+}
+
+// ServeHTTP handles OTLP/HTTP on /v1/traces, both the protobuf and the JSON encoding
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request := &collectortrace.ExportTraceServiceRequest{}
+	isJson := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+	if isJson {
+		err = protojson.Unmarshal(body, request)
+	} else {
+		err = proto.Unmarshal(body, request)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	response, _ := rc.Export(r.Context(), request)
+	var bytes []byte
+	if isJson {
+		bytes, _ = protojson.Marshal(response)
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		bytes, _ = proto.Marshal(response)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}
+	w.Write(bytes)
+}
+
+func fromResourceSpans(resourceSpans []*tracepb.ResourceSpans) []*tracestore.Span {
+	var spans []*tracestore.Span
+	for _, rs := range resourceSpans {
+		service := ""
+		for _, kv := range rs.GetResource().GetAttributes() {
+			if kv.Key == "service.name" {
+				service = anyValue(kv.Value)
+			}
+		}
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			for _, s := range ils.Spans {
+				spans = append(spans, fromSpan(s, service))
+			}
+		}
+	}
+	return spans
+}
+
+func fromSpan(s *tracepb.Span, service string) *tracestore.Span {
+	span := &tracestore.Span{
+		TraceID:    hex.EncodeToString(s.TraceId),
+		SpanID:     hex.EncodeToString(s.SpanId),
+		Name:       s.Name,
+		Kind:       spanKind(s.Kind),
+		Service:    service,
+		StartTime:  time.Unix(0, int64(s.StartTimeUnixNano)),
+		EndTime:    time.Unix(0, int64(s.EndTimeUnixNano)),
+		Attributes: attributes(s.Attributes),
+	}
+	if len(s.ParentSpanId) > 0 {
+		span.ParentSpanID = hex.EncodeToString(s.ParentSpanId)
+	}
+	switch s.GetStatus().GetCode() {
+	case tracepb.Status_STATUS_CODE_OK:
+		span.Status = "Ok"
+	case tracepb.Status_STATUS_CODE_ERROR:
+		span.Status = "Error"
+		span.StatusMessage = s.Status.Message
+	}
+	for _, e := range s.Events {
+		span.Events = append(span.Events, tracestore.Event{
+			Name:       e.Name,
+			Time:       time.Unix(0, int64(e.TimeUnixNano)),
+			Attributes: attributes(e.Attributes),
+		})
+	}
+	return span
+}
+
+func spanKind(kind tracepb.Span_SpanKind) string {
+	switch kind {
+	case tracepb.Span_SPAN_KIND_SERVER:
+		return "server"
+	case tracepb.Span_SPAN_KIND_CLIENT:
+		return "client"
+	case tracepb.Span_SPAN_KIND_PRODUCER:
+		return "producer"
+	case tracepb.Span_SPAN_KIND_CONSUMER:
+		return "consumer"
+	default:
+		return "internal"
+	}
+}
+
+func attributes(kvs []*commonpb.KeyValue) map[string]string {
+	if len(kvs) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = anyValue(kv.Value)
+	}
+	return m
+}
+
+func anyValue(v *commonpb.AnyValue) string {
+	switch value := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return value.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(value.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(value.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(value.DoubleValue, 'g', -1, 64)
+	default:
+		return protojson.Format(v)
+	}
+}
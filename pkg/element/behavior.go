@@ -0,0 +1,68 @@
+package element
+
+import (
+	"math"
+	"time"
+)
+
+// Behavior turns the properties of an element into the behavior of its atom, the behavior section of
+// elements.yaml configures it
+type Behavior struct {
+	LatencyBase      float64  `yaml:"latency_base"`
+	LatencyPerWeight float64  `yaml:"latency_per_weight"`
+	RadioactiveFrom  int32    `yaml:"radioactive_from"`
+	RadioactiveTo    int32    `yaml:"radioactive_to"`
+	DecayRate        float64  `yaml:"decay_rate"`
+	DecayPerNumber   float64  `yaml:"decay_per_number"`
+	Inert            []string `yaml:"inert"`
+	Reactive         []string `yaml:"reactive"`
+	ReactiveLogs     int      `yaml:"reactive_logs"`
+}
+
+// DefaultBehavior is used for the settings an elements file leaves out
+var DefaultBehavior = Behavior{
+	LatencyBase:      1,
+	LatencyPerWeight: 0.2,
+	RadioactiveFrom:  84,
+	RadioactiveTo:    118,
+	DecayRate:        2,
+	DecayPerNumber:   0.5,
+	Inert:            []string{NobleGas.String()},
+	Reactive:         []string{AlkaliMetal.String()},
+	ReactiveLogs:     5,
+}
+
+// Latency grows with the atomic weight, the number stands in for an unknown weight
+func (b *Behavior) Latency(atom Atom) time.Duration {
+	weight := float64(atom.Number)
+	if atom.Weight != nil {
+		weight = atom.Weight.Value
+	}
+	ms := b.LatencyBase + b.LatencyPerWeight*weight
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// DecayPercent is the chance in percent a call to a radioactive atom fails, 0 for stable and inert atoms
+func (b *Behavior) DecayPercent(atom Atom) float64 {
+	if atom.Number < b.RadioactiveFrom || atom.Number > b.RadioactiveTo || contains(b.Inert, atom.Category) {
+		return 0
+	}
+	return math.Min(100, b.DecayRate+b.DecayPerNumber*float64(atom.Number-b.RadioactiveFrom))
+}
+
+// ExtraLogs is the number of extra log lines a reactive atom writes
+func (b *Behavior) ExtraLogs(atom Atom) int {
+	if contains(b.Reactive, atom.Category) {
+		return b.ReactiveLogs
+	}
+	return 0
+}
+
+func contains(categories []string, category Category) bool {
+	for _, c := range categories {
+		if c == category.String() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package element
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBehavior(t *testing.T) {
+	atoms, err := Load("")
+	assert.NoError(t, err)
+	behavior := &atoms.Behavior
+
+	hydrogen := atoms.ElementByName["h"]
+	uranium := atoms.ElementByName["u"]
+	assert.Equal(t, time.Duration((1+0.2*1.008)*float64(time.Millisecond)), behavior.Latency(hydrogen))
+	assert.True(t, behavior.Latency(uranium) > 40*time.Millisecond)
+
+	assert.Equal(t, 0.0, behavior.DecayPercent(hydrogen))
+	assert.Equal(t, 2+0.5*(92-84), behavior.DecayPercent(uranium))
+	assert.Equal(t, 0.0, behavior.DecayPercent(atoms.ElementByName["rn"]), "radon is a noble gas")
+	assert.Equal(t, 0.0, behavior.DecayPercent(atoms.ElementByName["ai"]), "fictional elements are stable")
+
+	assert.Equal(t, 5, behavior.ExtraLogs(atoms.ElementByName["na"]))
+	assert.Equal(t, 0, behavior.ExtraLogs(hydrogen))
+}
@@ -0,0 +1,70 @@
+package element
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Category is the group of elements with similar properties, the property in elements.yaml
+type Category int
+
+const (
+	CategoryUnknown Category = iota
+	AlkaliMetal
+	AlkalineEarthMetal
+	Lanthanide
+	Actinide
+	TransitionMetal
+	PostTransitionMetal
+	Metalloid
+	PolyatomicNonmetal
+	DiatomicNonmetal
+	NobleGas
+)
+
+var categories = []struct {
+	key  string
+	name string
+}{
+	CategoryUnknown:     {"unknown", "Unknown"},
+	AlkaliMetal:         {"alkali_metal", "Alkali metal"},
+	AlkalineEarthMetal:  {"alkaline_earth_metal", "Alkaline earth metal"},
+	Lanthanide:          {"lanthanide", "Lanthanide"},
+	Actinide:            {"actinide", "Actinide"},
+	TransitionMetal:     {"transition_metal", "Transition metal"},
+	PostTransitionMetal: {"post_transition_metal", "Post-transition metal"},
+	Metalloid:           {"metalloid", "Metalloid"},
+	PolyatomicNonmetal:  {"polyatomic_nonmetal", "Polyatomic nonmetal"},
+	DiatomicNonmetal:    {"diatomic_nonmetal", "Diatomic nonmetal"},
+	NobleGas:            {"noble_gas", "Noble gas"},
+}
+
+// invisibleRunes are the soft hyphens and zero width spaces copied along from the source
+var invisibleRunes = strings.NewReplacer("\u00ad", "", "\u200b", "")
+
+// ParseCategory parses the property of an element, like noble_gas
+func ParseCategory(property string) (Category, error) {
+	key := invisibleRunes.Replace(strings.TrimSpace(property))
+	for c, category := range categories {
+		if category.key == key {
+			return Category(c), nil
+		}
+	}
+	return CategoryUnknown, fmt.Errorf("unknown property %q", property)
+}
+
+// String is the key, like noble_gas
+func (c Category) String() string {
+	if c < 0 || int(c) >= len(categories) {
+		return categories[CategoryUnknown].key
+	}
+	return categories[c].key
+}
+
+// Name is the human readable name, like Noble gas
+func (c Category) Name() string {
+	if c < 0 || int(c) >= len(categories) {
+		return categories[CategoryUnknown].name
+	}
+	return categories[c].name
+}
@@ -1,18 +1,26 @@
 package element
 
 import (
+	_ "embed"
+	"fmt"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
-	"log"
+	"regexp"
 	"strings"
 )
 
+// embeddedElements is the periodic table the binary is built with
+//
+//go:embed elements.yaml
+var embeddedElements []byte
+
 type element struct {
-	Number    int32    `yaml:"number"`
+	Number    int32  `yaml:"number"`
 	Symbol    string `yaml:"symbol"`
 	Element   string `yaml:"element"`
-	Group     int32    `yaml:"group"`
-	Period    int32    `yaml:"period"`
+	Origin    string `yaml:"origin"`
+	Group     int32  `yaml:"group"`
+	Period    int32  `yaml:"period"`
 	Weight    string `yaml:"weight"`
 	Density   string `yaml:"density"`
 	Melt      string `yaml:"melt"`
@@ -24,57 +32,141 @@ type element struct {
 }
 
 type elements struct {
-	Source   string     `yaml:"source"`
-	Elements [] element `yaml:"elements"`
+	Source   string    `yaml:"source"`
+	Behavior Behavior  `yaml:"behavior"`
+	Elements []element `yaml:"elements"`
 }
 
-func readElements() elements {
-	content, err := ioutil.ReadFile("elements.yaml")
-	if err != nil {
-		log.Fatal(err)
+func readElements(content []byte) (elements, error) {
+	e := elements{Behavior: DefaultBehavior}
+	if err := yaml.Unmarshal(content, &e); err != nil {
+		return e, err
 	}
-
-	e := elements{}
-	err = yaml.Unmarshal([]byte(content), &e)
-	if err != nil {
-		log.Fatalf("error: %v", err)
+	if len(e.Elements) == 0 {
+		return e, fmt.Errorf("no elements")
 	}
-	return e
+	return e, nil
 }
 
+// Atom is an element of elements.yaml, unknown quantities are nil
 type Atom struct {
-	Name   string
-	Symbol string
-	Number int32
-	Period int32
-	Group  int32
+	Name     string
+	Symbol   string
+	Number   int32
+	Period   int32
+	Group    int32 // 0 for the lanthanides and actinides
+	Origin   string
+	Category Category
+
+	Weight            *Quantity // standard atomic weight (Da)
+	Density           *Quantity // density (g/cm³)
+	Melt              *Quantity // melting point (K)
+	Boil              *Quantity // boiling point (K)
+	HeatCapacity      *Quantity // specific heat capacity (J/g·K)
+	Electronegativity *Quantity // electronegativity (Pauling scale)
+	Abundance         *Quantity // abundance in the earth's crust (mg/kg)
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// newAtom converts the element, the problems are reported and the affected values left out
+func newAtom(e element) (Atom, []error) {
+	var problems []error
+	quantity := func(name string, value string) *Quantity {
+		q, err := ParseQuantity(value)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", name, err))
+		}
+		return q
+	}
+	atom := Atom{
+		Symbol:            e.Symbol,
+		Name:              e.Element,
+		Number:            e.Number,
+		Period:            e.Period,
+		Group:             e.Group,
+		Origin:            htmlTagPattern.ReplaceAllString(e.Origin, ""),
+		Weight:            quantity("weight", e.Weight),
+		Density:           quantity("density", e.Density),
+		Melt:              quantity("melt", e.Melt),
+		Boil:              quantity("boil", e.Boil),
+		HeatCapacity:      quantity("C", e.C),
+		Electronegativity: quantity("X", e.X),
+		Abundance:         quantity("abundance", e.Abundance),
+	}
+	category, err := ParseCategory(e.Property)
+	if err != nil {
+		problems = append(problems, err)
+	}
+	atom.Category = category
+	if e.Number <= 0 {
+		problems = append(problems, fmt.Errorf("number needs to be positive"))
+	}
+	if e.Symbol == "" || e.Element == "" {
+		problems = append(problems, fmt.Errorf("symbol and element are required"))
+	}
+	if e.Period <= 0 {
+		problems = append(problems, fmt.Errorf("period needs to be positive"))
+	}
+	return atom, problems
 }
 
 type Atoms struct {
 	ElementByName   map[string]Atom
 	ElementByNumber map[int32]Atom
+	Behavior        Behavior
+	// Problems are the malformed entries found while loading
+	Problems []error
 }
 
-func (a *Atoms) read() {
+func (a *Atoms) read(source string, content []byte) error {
 	a.ElementByName = make(map[string]Atom)
 	a.ElementByNumber = make(map[int32]Atom)
-	elements := readElements()
+	elements, err := readElements(content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	a.Behavior = elements.Behavior
+	for _, categories := range [][]string{a.Behavior.Inert, a.Behavior.Reactive} {
+		for _, category := range categories {
+			if _, err := ParseCategory(category); err != nil {
+				a.Problems = append(a.Problems, fmt.Errorf("%s: behavior: %w", source, err))
+			}
+		}
+	}
 
-	for _, e := range elements.Elements {
-		atom := Atom{
-			Symbol: e.Symbol,
-			Name:   e.Element,
-			Number: e.Number,
-			Period: e.Period,
-			Group:  e.Group,
+	for i, e := range elements.Elements {
+		atom, problems := newAtom(e)
+		if _, ok := a.ElementByNumber[e.Number]; ok {
+			problems = append(problems, fmt.Errorf("duplicate number"))
+		}
+		if _, ok := a.ElementByName[strings.ToLower(e.Symbol)]; ok {
+			problems = append(problems, fmt.Errorf("duplicate symbol"))
+		}
+		for _, problem := range problems {
+			a.Problems = append(a.Problems, fmt.Errorf("%s: entry %d (%d %s): %w", source, i+1, e.Number, e.Symbol, problem))
 		}
 		a.ElementByName[strings.ToLower(e.Symbol)] = atom
 		a.ElementByNumber[e.Number] = atom
 	}
+	return nil
 }
 
-func NewAtoms() *Atoms {
-	atom := &Atoms{}
-	atom.read()
-	return atom
+// Load reads the periodic table from the file, or the embedded one when the path is empty. Malformed entries
+// don't fail the load, they are in Problems.
+func Load(path string) (*Atoms, error) {
+	source, content := "embedded elements.yaml", embeddedElements
+	if path != "" {
+		var err error
+		source = path
+		content, err = ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	atoms := &Atoms{}
+	if err := atoms.read(source, content); err != nil {
+		return nil, err
+	}
+	return atoms, nil
 }
@@ -0,0 +1,131 @@
+package element
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuantity(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		expected *Quantity
+	}{
+		{"1.008", &Quantity{Value: 1.008}},
+		{"4.002602(2)", &Quantity{Value: 4.002602, Uncertainty: 0.000002}},
+		{"173.045(10)", &Quantity{Value: 173.045, Uncertainty: 0.01}},
+		{`10.81<sup id="cite_ref-fn2_9-3" class="reference"><a href="#cite_note-fn2-9">[III]</sup>`, &Quantity{Value: 10.81}},
+		{"[294]", &Quantity{Value: 294, MassNumber: true}},
+		{"(5.0)", &Quantity{Value: 5, Estimated: true}},
+		{"~210", &Quantity{Value: 210, Approximate: true}},
+		{"1.4×10<sup>−6</sup>", &Quantity{Value: 1.4e-6}},
+		{"≤&#160;3×10<sup>−11</sup>", &Quantity{Value: 3e-11, UpperBound: true}},
+		{"–", nil},
+		{"—", nil},
+		{"", nil},
+	} {
+		q, err := ParseQuantity(test.in)
+		assert.NoError(t, err, test.in)
+		if test.expected == nil {
+			assert.Nil(t, q, test.in)
+			continue
+		}
+		assert.InDelta(t, test.expected.Value, q.Value, 1e-12*test.expected.Value, test.in)
+		assert.InDelta(t, test.expected.Uncertainty, q.Uncertainty, 1e-12, test.in)
+		q.Value, q.Uncertainty = test.expected.Value, test.expected.Uncertainty
+		assert.Equal(t, test.expected, q, test.in)
+	}
+
+	_, err := ParseQuantity("heavy")
+	assert.Error(t, err)
+}
+
+func TestParseCategory(t *testing.T) {
+	c, err := ParseCategory("lan\u00adthanide")
+	assert.NoError(t, err)
+	assert.Equal(t, Lanthanide, c)
+	assert.Equal(t, "lanthanide", c.String())
+
+	c, err = ParseCategory("post_\u200btransition_metal")
+	assert.NoError(t, err)
+	assert.Equal(t, "Post-transition metal", c.Name())
+
+	_, err = ParseCategory("")
+	assert.Error(t, err)
+}
+
+func TestParseQuery(t *testing.T) {
+	for _, test := range []struct {
+		in       map[string]string
+		expected Query
+	}{
+		{map[string]string{"group": "18"}, Query{Group: 18}},
+		{map[string]string{"period": "2", "property": "metalloid"}, Query{Period: 2, Category: Metalloid, HasCategory: true}},
+		{map[string]string{"number": "1..10"}, Query{From: 1, To: 10}},
+		{map[string]string{"number": "8", "times": "2"}, Query{From: 8, To: 8}},
+	} {
+		q, err := ParseQuery(test.in)
+		assert.NoError(t, err, test.in)
+		assert.Equal(t, test.expected, *q, test.in)
+	}
+
+	for _, in := range []map[string]string{
+		{"group": "0"}, {"period": "x"}, {"property": "exotic"}, {"number": "0"}, {"number": "-1"}, {"number": "0..0"},
+		{"number": "0..10"}, {"number": "10..1"},
+	} {
+		_, err := ParseQuery(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestLoadEmbedded(t *testing.T) {
+	atoms, err := Load("")
+	assert.NoError(t, err)
+	assert.Empty(t, atoms.Problems)
+
+	helium := atoms.ElementByName["he"]
+	assert.Equal(t, NobleGas, helium.Category)
+	assert.Equal(t, "the Greek helios, 'sun'", helium.Origin)
+	assert.Equal(t, 4.002602, helium.Weight.Value)
+	assert.Nil(t, helium.Melt)
+
+	beryllium := atoms.ElementByNumber[4]
+	assert.Equal(t, AlkalineEarthMetal, beryllium.Category)
+}
+
+func TestLoadFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "elements")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "elements.yaml")
+	ioutil.WriteFile(path, []byte(`
+elements:
+- number: 1
+  symbol: "Tr"
+  element: "Treactorium"
+  period: 1
+  weight: 1.5
+  property: "unknown"
+- number: 2
+  symbol: "Bg"
+  element: "Buggium"
+  period: 1
+  weight: heavy
+  property: "exotic"
+`), 0644)
+
+	atoms, err := Load(path)
+	assert.NoError(t, err)
+	assert.Len(t, atoms.ElementByNumber, 2)
+	assert.Equal(t, "Treactorium", atoms.ElementByName["tr"].Name)
+	assert.Len(t, atoms.Problems, 2)
+
+	_, err = Load(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+
+	ioutil.WriteFile(path, []byte("elements: [1"), 0644)
+	_, err = Load(path)
+	assert.Error(t, err)
+}
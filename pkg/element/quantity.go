@@ -0,0 +1,106 @@
+package element
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Quantity is a value from the element table. The notation of the source is kept in the flags:
+//
+//	4.002602(2)   Value 4.002602, Uncertainty 0.000002
+//	(5.0)         Estimated, a predicted value
+//	[294]         MassNumber, the mass number of the most stable isotope
+//	~210          Approximate
+//	≤ 3×10⁻¹¹     UpperBound
+type Quantity struct {
+	Value       float64
+	Uncertainty float64
+	Estimated   bool
+	MassNumber  bool
+	Approximate bool
+	UpperBound  bool
+}
+
+var (
+	// citations like <sup id="cite_ref-fn2_9-3" class="reference"><a href="#cite_note-fn2-9">[III]</sup>
+	citationPattern = regexp.MustCompile(`<sup id="cite_ref[^>]*>.*?</sup>`)
+	// scientific notation like 1.4×10<sup>−6</sup>
+	scientificPattern = regexp.MustCompile(`^([0-9.]+)×10<sup>([−-]?[0-9]+)</sup>$`)
+	// uncertainty in the last digits like 4.002602(2)
+	uncertaintyPattern = regexp.MustCompile(`^([0-9]+)(?:\.([0-9]+))?\(([0-9]+)\)$`)
+)
+
+// ParseQuantity parses a value of the element table, a dash means unknown and returns nil
+func ParseQuantity(value string) (*Quantity, error) {
+	s := citationPattern.ReplaceAllString(value, "")
+	s = strings.TrimSpace(strings.ReplaceAll(s, "&#160;", " "))
+	if s == "" || s == "–" || s == "—" || s == "-" {
+		return nil, nil
+	}
+
+	q := &Quantity{}
+	if strings.HasPrefix(s, "~") {
+		q.Approximate = true
+		s = strings.TrimSpace(strings.TrimPrefix(s, "~"))
+	} else if strings.HasPrefix(s, "≤") {
+		q.UpperBound = true
+		s = strings.TrimSpace(strings.TrimPrefix(s, "≤"))
+	}
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		q.Estimated = true
+		s = s[1 : len(s)-1]
+	} else if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		q.MassNumber = true
+		s = s[1 : len(s)-1]
+	}
+
+	if m := scientificPattern.FindStringSubmatch(s); m != nil {
+		mantissa, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed quantity %q", value)
+		}
+		exponent, err := strconv.Atoi(strings.Replace(m[2], "−", "-", 1))
+		if err != nil {
+			return nil, fmt.Errorf("malformed quantity %q", value)
+		}
+		q.Value = mantissa * math.Pow10(exponent)
+		return q, nil
+	}
+	if m := uncertaintyPattern.FindStringSubmatch(s); m != nil {
+		q.Value, _ = strconv.ParseFloat(m[1]+"."+m[2], 64)
+		digits, _ := strconv.Atoi(m[3])
+		q.Uncertainty = float64(digits) * math.Pow10(-len(m[2]))
+		return q, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed quantity %q", value)
+	}
+	q.Value = v
+	return q, nil
+}
+
+func (q *Quantity) String() string {
+	if q == nil {
+		return "–"
+	}
+	s := strconv.FormatFloat(q.Value, 'g', -1, 64)
+	switch {
+	case q.Estimated:
+		s = "(" + s + ")"
+	case q.MassNumber:
+		s = "[" + s + "]"
+	}
+	if q.Uncertainty > 0 {
+		s += "±" + strconv.FormatFloat(q.Uncertainty, 'g', -1, 64)
+	}
+	if q.Approximate {
+		s = "~" + s
+	} else if q.UpperBound {
+		s = "≤" + s
+	}
+	return s
+}
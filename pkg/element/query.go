@@ -0,0 +1,97 @@
+package element
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Query selects atoms from the table, all the criteria that are set have to match
+type Query struct {
+	Group       int32
+	Period      int32
+	Category    Category
+	HasCategory bool
+	From        int32
+	To          int32
+}
+
+var rangePattern = regexp.MustCompile(`^([0-9]+)\.\.([0-9]+)$`)
+
+// IsQueryKey tells if the key is a criterion of a query
+func IsQueryKey(key string) bool {
+	return key == "group" || key == "period" || key == "property" || key == "number"
+}
+
+// IsRange tells if the value is a range of numbers, like 1..10
+func IsRange(value string) bool {
+	return rangePattern.MatchString(value)
+}
+
+// ParseQuery parses the criteria group:18, period:2, property:noble_gas and number:1..10, other keys are ignored
+func ParseQuery(criteria map[string]string) (*Query, error) {
+	q := &Query{}
+	for key, value := range criteria {
+		switch key {
+		case "group":
+			group, err := strconv.Atoi(value)
+			if err != nil || group <= 0 {
+				return nil, fmt.Errorf("group needs a positive number")
+			}
+			q.Group = int32(group)
+		case "period":
+			period, err := strconv.Atoi(value)
+			if err != nil || period <= 0 {
+				return nil, fmt.Errorf("period needs a positive number")
+			}
+			q.Period = int32(period)
+		case "property":
+			category, err := ParseCategory(value)
+			if err != nil {
+				return nil, err
+			}
+			q.Category, q.HasCategory = category, true
+		case "number":
+			m := rangePattern.FindStringSubmatch(value)
+			if m == nil {
+				number, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("number needs a number or a range like 1..10")
+				}
+				m = []string{value, strconv.Itoa(number), strconv.Itoa(number)}
+			}
+			from, _ := strconv.Atoi(m[1])
+			to, _ := strconv.Atoi(m[2])
+			if from < 1 {
+				return nil, fmt.Errorf("number needs numbers from 1")
+			}
+			if from > to {
+				return nil, fmt.Errorf("range %s is empty", value)
+			}
+			q.From, q.To = int32(from), int32(to)
+		}
+	}
+	return q, nil
+}
+
+func (q *Query) Matches(atom Atom) bool {
+	return (q.Group == 0 || q.Group == atom.Group) &&
+		(q.Period == 0 || q.Period == atom.Period) &&
+		(!q.HasCategory || q.Category == atom.Category) &&
+		(q.To == 0 || (atom.Number >= q.From && atom.Number <= q.To))
+}
+
+// Select returns the matching atoms ordered by number
+func (a *Atoms) Select(q *Query) []Atom {
+	var atoms []Atom
+	for _, atom := range a.ElementByNumber {
+		if q.Matches(atom) {
+			atoms = append(atoms, atom)
+		}
+	}
+	sort.Slice(atoms, func(i, j int) bool {
+		return atoms[i].Number < atoms[j].Number
+	})
+	return atoms
+}
@@ -0,0 +1,83 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxBlobBytes limits the size of the payload and body actions
+const maxBlobBytes = 64 * 1024 * 1024
+
+const (
+	// blobAlphabet keeps a random blob valid in a JSON string without escaping
+	blobAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	blobText     = "The quick brown fox jumps over the lazy dog. "
+)
+
+// BlobSpec is the value of the payload and body KV annotations:
+//
+//	N                     N bytes of random characters, they don't compress (k and m suffixes allowed)
+//	N@random              the same
+//	N@text                N bytes of repeated text, they compress well
+type BlobSpec struct {
+	Size int
+	Mode string
+}
+
+func ParseBlob(action string, value string) (*BlobSpec, error) {
+	spec := strings.SplitN(value, "@", 2)
+	if spec[0] == "" {
+		return nil, fmt.Errorf("%s needs a size, like %s:64k", action, action)
+	}
+	size, err := resource.ParseSize(spec[0])
+	if err != nil || size < 0 || size > maxBlobBytes {
+		return nil, fmt.Errorf("%s needs a size up to %d bytes", action, maxBlobBytes)
+	}
+	b := &BlobSpec{Size: size, Mode: "random"}
+	if len(spec) == 2 {
+		b.Mode = spec[1]
+	}
+	if b.Mode != "random" && b.Mode != "text" {
+		return nil, fmt.Errorf("unknown %s mode %s", action, b.Mode)
+	}
+	return b, nil
+}
+
+// Generate returns the blob, a random blob is drawn from the seed so a reaction replays the same bytes
+func (b *BlobSpec) Generate(ctx context.Context, action string) []byte {
+	blob := make([]byte, b.Size)
+	if b.Mode == "text" {
+		for i := range blob {
+			blob[i] = blobText[i%len(blobText)]
+		}
+		return blob
+	}
+	random := Rand(ctx, action)
+	for i := range blob {
+		blob[i] = blobAlphabet[random.Intn(len(blobAlphabet))]
+	}
+	return blob
+}
+
+// requestBody generates the body of the body KV annotation, a call with a body is a POST
+func requestBody(ctx context.Context, value string) []byte {
+	if value == "" {
+		return nil
+	}
+	spec, err := ParseBlob("body", value)
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Ignoring body action: %s", err)
+		return nil
+	}
+	trace.SpanFromContext(ctx).AddEvent("body", trace.WithAttributes(
+		resource.ActionKey.String("body"),
+		resource.ActionValueKey.String(value),
+		attribute.Int("treactor.body.bytes", spec.Size),
+		attribute.String("treactor.body.mode", spec.Mode)))
+	return spec.Generate(ctx, "body")
+}
@@ -0,0 +1,108 @@
+package execute
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/element"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/context"
+)
+
+// RandomAtom is the block content that picks a random atom, like [?] or [?,cpu:10]
+const RandomAtom = "?"
+
+// Choice picks one of the branches per execution, [H]|3[O] executes O three times as often as H. The number in
+// front of a branch is its weight instead of the repetitions.
+type Choice struct {
+	index    int
+	branches []Plan
+	weights  []int
+}
+
+// newChoice turns the repetitions of the branches into their weights
+func newChoice(branches []Plan) (*Choice, error) {
+	choice := &Choice{branches: branches}
+	for _, branch := range branches {
+		var weight *int
+		switch b := branch.(type) {
+		case *Block:
+			weight = &b.times
+		case *Selection:
+			weight = &b.times
+		default:
+			return nil, errors.New("Choice needs blocks")
+		}
+		if *weight <= 0 {
+			return nil, errors.New("Choice weight needs to be positive")
+		}
+		choice.weights = append(choice.weights, *weight)
+		*weight = 1
+	}
+	return choice, nil
+}
+
+// pick returns the index of the branch, drawn by weight
+func (o *Choice) pick(ctx context.Context) int {
+	total := 0
+	for _, weight := range o.weights {
+		total += weight
+	}
+	n := Rand(ctx, "choice", o.index).Intn(total)
+	for i, weight := range o.weights {
+		if n < weight {
+			return i
+		}
+		n -= weight
+	}
+	return len(o.weights) - 1
+}
+
+func (o *Choice) Execute(ctx context.Context, channel chan *treactorpb.Bond) {
+	picked := o.pick(ctx)
+	branch := o.branchString(picked)
+	ctx, span := resource.StartSpan(ctx, resource.GranularityPlan, "Execute Choice", trace.WithAttributes(
+		resource.PlanKey.String(o.String()),
+		resource.BlockIndexKey.Int(o.index),
+		resource.ChoiceKey.String(branch),
+		resource.ChoiceBranchKey.Int(picked),
+		resource.SeedKey.Int64(Seed(ctx))))
+	defer span.End()
+	o.branches[picked].Execute(withChoice(ctx, branch), channel)
+}
+
+// Calls is the most calls a branch makes
+func (o *Choice) Calls() int {
+	calls := 0
+	for _, branch := range o.branches {
+		if branch.Calls() > calls {
+			calls = branch.Calls()
+		}
+	}
+	return calls
+}
+
+// branchString formats the branch with its weight in front, the branch itself is executed once
+func (o *Choice) branchString(i int) string {
+	return strconv.Itoa(o.weights[i]) + strings.TrimPrefix(o.branches[i].String(), "1")
+}
+
+func (o *Choice) String() string {
+	s := make([]string, len(o.branches))
+	for i := range o.branches {
+		s[i] = o.branchString(i)
+	}
+	return strings.Join(s, "|")
+}
+
+// randomAtom picks the atom for a [?] block, out of the atoms up to TREACTOR_MAX_NUMBER
+func randomAtom(ctx context.Context, index int, repetition int) (element.Atom, bool) {
+	atoms := resource.Atoms.Select(&element.Query{From: 1, To: int32(resource.MaxNumber)})
+	if len(atoms) == 0 {
+		return element.Atom{}, false
+	}
+	return atoms[Rand(ctx, "atom", index, repetition).Intn(len(atoms))], true
+}
@@ -0,0 +1,27 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// DepthHeader tells the called bond or atom how deep it is in the reaction, the reaction itself is 0
+const DepthHeader = "X-Treactor-Depth"
+
+type depthKey struct{}
+
+func WithDepth(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, depthKey{}, depth)
+}
+
+func Depth(ctx context.Context) int {
+	depth, _ := ctx.Value(depthKey{}).(int)
+	return depth
+}
+
+// DepthFromRequest reads the depth set by the caller
+func DepthFromRequest(r *http.Request) int {
+	depth, _ := strconv.Atoi(r.Header.Get(DepthHeader))
+	return depth
+}
@@ -19,13 +19,16 @@ const (
 	NUMBER
 
 	// Misc characters
-	MULTIPLY  // *
-	PLUS      // ^
-	COMMA     // ,
-	COLON     // :
-
-	BLOCK_START  // [
-	BLOCK_END    // ]
+	MULTIPLY // *
+	PLUS     // ^
+	COMMA    // ,
+	COLON    // :
+	AT       // @
+	PIPE     // |
+	PERCENT  // %
+
+	BLOCK_START // [
+	BLOCK_END   // ]
 )
 
 func isWhitespace(ch rune) bool {
@@ -102,6 +105,12 @@ func (s *Scanner) Scan() (tok Token, lit string) {
 		return COMMA, string(ch)
 	case ':':
 		return COLON, string(ch)
+	case '@':
+		return AT, string(ch)
+	case '|':
+		return PIPE, string(ch)
+	case '%':
+		return PERCENT, string(ch)
 	case '[':
 		return BLOCK_START, string(ch)
 	case ']':
@@ -11,6 +11,7 @@ import (
 // Parser represents a parser.
 type Parser struct {
 	scanner *Scanner
+	blocks  int // number of blocks parsed, used as index of the next block
 	buf     struct {
 		tok Token  // last read token
 		lit string // last read literal
@@ -53,12 +54,11 @@ func (p *Parser) parseKeyValues(kv map[string]string) (out map[string]string, er
 	if token != COLON {
 		return nil, errors.New("KV need :")
 	}
-	token, value := p.scan()
-	if token == WORD || token == NUMBER {
-		kv[key] = value
-	} else {
-		return nil, errors.New("KV needs value")
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
 	}
+	kv[key] = value
 
 	token, _ = p.scan()
 	if token == COMMA {
@@ -69,15 +69,34 @@ func (p *Parser) parseKeyValues(kv map[string]string) (out map[string]string, er
 	return kv, nil
 }
 
+// parseValue reads the value of a KV, a value can have options like log:5@warning or cpu:500ms@2x50%
+func (p *Parser) parseValue() (value string, err error) {
+	var buffer bytes.Buffer
+	for {
+		token, str := p.scan()
+		if token == WORD || token == NUMBER || token == AT || token == COLON || token == PERCENT {
+			buffer.WriteString(str)
+			continue
+		}
+		p.unscan()
+		break
+	}
+	if buffer.Len() == 0 {
+		return "", errors.New("KV needs value")
+	}
+	return buffer.String(), nil
+}
+
 func (p *Parser) collectBlockContent() (content string, err error) {
 	var buffer bytes.Buffer
 
-	for depth := 1; depth > 0;
-	{
+	for depth := 1; depth > 0; {
 		token, str := p.scan()
 		if token == BLOCK_START {
 			depth = depth + 1
 			buffer.Write([]byte(str))
+		} else if token == EOF {
+			return "", errors.New("Block needs ]")
 		} else if token == BLOCK_END {
 			depth = depth - 1
 			if depth > 0 {
@@ -90,7 +109,50 @@ func (p *Parser) collectBlockContent() (content string, err error) {
 	return buffer.String(), nil
 }
 
+// parseBlock parses the blocks joined by the operators, a choice between blocks binds stronger than an operator
 func (p *Parser) parseBlock() (plan Plan, err error) {
+	block, token, err := p.parseUnit()
+	if err != nil {
+		return nil, err
+	}
+
+	if token == PIPE {
+		branches := []Plan{block}
+		for token == PIPE {
+			block, token, err = p.parseUnit()
+			if err != nil {
+				return nil, err
+			}
+			branches = append(branches, block)
+		}
+		choice, err := newChoice(branches)
+		if err != nil {
+			return nil, err
+		}
+		choice.index = p.blocks
+		p.blocks++
+		block = choice
+	}
+
+	if token == PLUS || token == MULTIPLY {
+		next, err := p.parseBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		return &Operator{
+			operand: token,
+			left:    block,
+			right:   next,
+		}, nil
+	}
+
+	return block, nil
+
+}
+
+// parseUnit parses a single block or selection with its KVs, the token following it is returned
+func (p *Parser) parseUnit() (plan Plan, next Token, err error) {
 	times := 1
 	mode := "s"
 
@@ -106,7 +168,7 @@ func (p *Parser) parseBlock() (plan Plan, err error) {
 		if val == "p" || val == "s" {
 			mode = val
 		} else {
-			return nil, errors.New("Only s or p accepted")
+			return nil, ILLEGAL, errors.New("Only s or p accepted")
 		}
 
 		token, val = p.scan()
@@ -115,47 +177,47 @@ func (p *Parser) parseBlock() (plan Plan, err error) {
 	if token == BLOCK_START {
 		content, err = p.collectBlockContent()
 		if err != nil {
-			errors.New("")
+			return nil, ILLEGAL, err
 		}
-		_ = content
 		token, val = p.scan()
 	} else {
-		return nil, errors.New("Unknown token for Block")
+		return nil, ILLEGAL, errors.New("Unknown token for Block")
 	}
 
 	var kv map[string]string
 	if token == COMMA {
 		kv, err = p.parseKeyValues(make(map[string]string))
 		if err != nil {
-			return nil, err
+			return nil, ILLEGAL, err
 		}
 		token, _ = p.scan()
 	} else {
 		kv = make(map[string]string)
 	}
 
-	block := &Block{
-		times: times,
-		mode:  mode,
-		Block: content,
-		KV:    kv,
-	}
-
-	if token == PLUS || token == MULTIPLY {
-		next, err := p.parseBlock()
+	var block Plan
+	if isSelector(content) {
+		selection, err := newSelection(content)
 		if err != nil {
-			return nil, err
+			return nil, ILLEGAL, err
+		}
+		selection.index, selection.times, selection.mode, selection.KV = p.blocks, times, mode, kv
+		for _, b := range selection.Blocks {
+			b.index = p.blocks
+		}
+		block = selection
+	} else {
+		block = &Block{
+			index: p.blocks,
+			times: times,
+			mode:  mode,
+			Block: content,
+			KV:    kv,
 		}
-
-		return &Operator{
-			operand: token,
-			left:    block,
-			right:   next,
-		}, nil
 	}
+	p.blocks++
 
-	return block, nil
-
+	return block, token, nil
 }
 
 func (p *Parser) parseBlockContent() (plan *Block, err error) {
@@ -198,4 +260,4 @@ func Parse(molecule string) (plan Plan, err error) {
 func ParseBlock(block string) (*Block, error) {
 	parser := NewParser(strings.NewReader(block))
 	return parser.parseBlockContent()
-}
\ No newline at end of file
+}
@@ -1,18 +1,25 @@
 package execute
 
 import (
-	"fmt"
+	"bytes"
+	"errors"
 	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
 	"github.com/treactor/treactor-go/pkg/resource"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/context"
 	"google.golang.org/protobuf/encoding/protojson"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptrace"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 	"unicode"
 )
 
@@ -23,6 +30,7 @@ type Plan interface {
 }
 
 type Block struct {
+	index int
 	times int
 	mode  string
 	Block string
@@ -30,45 +38,84 @@ type Block struct {
 }
 
 func (o *Block) isAtom() bool {
-	return unicode.IsLetter(rune(o.Block[0]))
+	return unicode.IsLetter(rune(o.Block[0])) || o.isRandomAtom()
 }
 
-func (o *Block) callElement(ctx context.Context, wg *sync.WaitGroup, channel chan  *treactorpb.Bond) {
+func (o *Block) isRandomAtom() bool {
+	return strings.Split(o.Block, ",")[0] == RandomAtom
+}
+
+func (o *Block) callElement(ctx context.Context, wg *sync.WaitGroup, channel chan *treactorpb.Bond, repetition int) {
 	defer wg.Done()
-	// If REACTOR_TRACE_INTERNAL=1 add internal spans
-	ctx, span := resource.Tracer.Start(ctx, "Block [callElement]")
+	content := o.Block
+	if o.isRandomAtom() {
+		atom, ok := randomAtom(ctx, o.index, repetition)
+		if !ok {
+			resource.Logger.WarningF(ctx, "No atom to pick for %s", o.Block)
+			return
+		}
+		content = atom.Symbol + strings.TrimPrefix(o.Block, RandomAtom)
+		ctx = withChoice(ctx, atom.Symbol)
+	}
+	symbol := strings.Split(content, ",")[0]
+	ctx = withHop(ctx, o.index, repetition, symbol)
+	// the body of an atom is set in the atom, like [H,body:1m], or on the block, like [H],body:1m
+	bodyValue := o.KV["body"]
+	if atom, err := ParseBlock(content); err == nil && atom.KV["body"] != "" {
+		bodyValue = atom.KV["body"]
+	}
+	ctx, span := resource.StartSpan(ctx, resource.GranularityVerbose, "Block [callElement]", trace.WithAttributes(
+		resource.BlockIndexKey.Int(o.index),
+		resource.RepetitionKey.Int(repetition),
+		resource.AtomSymbolKey.String(symbol),
+		resource.HopPathKey.String(Path(ctx))))
 	defer span.End()
-	CallElementResource(ctx, channel, o.Block)
+	if o.isRandomAtom() {
+		span.SetAttributes(resource.ChoiceKey.String(symbol), resource.SeedKey.Int64(Seed(ctx)))
+	}
+	CallElementResource(ctx, channel, content, requestBody(ctx, bodyValue))
 }
 
-func (o *Block) callBond(ctx context.Context, wg *sync.WaitGroup, channel chan  *treactorpb.Bond) {
+func (o *Block) callBond(ctx context.Context, wg *sync.WaitGroup, channel chan *treactorpb.Bond, repetition int) {
 	defer wg.Done()
-	// If REACTOR_TRACE_INTERNAL=1 add internal spans
-	ctx, span := resource.Tracer.Start(ctx, "Block [callBond]")
+	ctx = withHop(ctx, o.index, repetition, "")
+	ctx, span := resource.StartSpan(ctx, resource.GranularityVerbose, "Block [callBond]", trace.WithAttributes(
+		resource.BlockIndexKey.Int(o.index),
+		resource.RepetitionKey.Int(repetition),
+		resource.MoleculeKey.String(o.Block),
+		resource.BondDepthKey.Int(Depth(ctx)+1),
+		resource.HopPathKey.String(Path(ctx))))
 	defer span.End()
-	CallBondResource(ctx, channel, o.Block)
+	CallBondResource(ctx, channel, o.Block, requestBody(ctx, o.KV["body"]))
 }
 
-func (o *Block) Execute(ctx context.Context, channel chan  *treactorpb.Bond) {
-	// If REACTOR_TRACE_INTERNAL=1 add internal spans
-	ctx, span := resource.Tracer.Start(ctx, "Execute Block")
+func (o *Block) Execute(ctx context.Context, channel chan *treactorpb.Bond) {
+	ctx, span := resource.StartSpan(ctx, resource.GranularityPlan, "Execute Block", trace.WithAttributes(
+		resource.PlanKey.String(o.String()),
+		resource.BlockIndexKey.Int(o.index),
+		resource.ModeKey.String(o.mode),
+		resource.TimesKey.Int(o.times)))
 	defer span.End()
+	span.SetAttributes(resource.KVAttributes(o.KV)...)
+	if o.KV["log"] != "" {
+		resource.EmitLogs(ctx, o.KV)
+	}
 	wg := sync.WaitGroup{}
 	wg.Add(o.times)
 	if o.mode == "s" {
 		for i := 1; i <= o.times; i++ {
 			if o.isAtom() {
-				o.callElement(ctx, &wg, channel)
+				o.callElement(ctx, &wg, channel, i)
 			} else {
-				o.callBond(ctx, &wg, channel)
+				o.callBond(ctx, &wg, channel, i)
 			}
 		}
 	} else if o.mode == "p" {
 		for i := 1; i <= o.times; i++ {
 			if o.isAtom() {
-				go o.callElement(ctx, &wg, channel)
+				go o.callElement(ctx, &wg, channel, i)
 			} else {
-				go o.callBond(ctx, &wg, channel)
+				go o.callBond(ctx, &wg, channel, i)
 			}
 		}
 	} else {
@@ -82,9 +129,19 @@ func (o *Block) Calls() int {
 }
 
 func (o *Block) String() string {
-	s := strconv.Itoa(o.times) + o.mode + "[" + o.Block + "]"
-	for k, v := range o.KV {
-		s += "," + k + ":" + v
+	return strconv.Itoa(o.times) + o.mode + "[" + o.Block + "]" + kvString(o.KV)
+}
+
+// kvString formats the KVs sorted by key, like ,log:1,x:2
+func kvString(kv map[string]string) string {
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	s := ""
+	for _, k := range keys {
+		s += "," + k + ":" + kv[k]
 	}
 	return s
 }
@@ -95,9 +152,9 @@ type Operator struct {
 	operand Token
 }
 
-func (o *Operator) Execute(ctx context.Context, channel chan  *treactorpb.Bond) {
-	// If REACTOR_TRACE_INTERNAL=1 add internal spans
-	ctx, span := resource.Tracer.Start(ctx, "Execute Operator")
+func (o *Operator) Execute(ctx context.Context, channel chan *treactorpb.Bond) {
+	ctx, span := resource.StartSpan(ctx, resource.GranularityPlan, "Execute Operator", trace.WithAttributes(
+		resource.PlanKey.String(o.String())))
 	defer span.End()
 	wg := sync.WaitGroup{}
 	wg.Add(2)
@@ -117,9 +174,10 @@ func (o *Operator) Calls() int {
 	return o.left.Calls() + o.right.Calls()
 }
 
-func (o *Operator) execute(ctx context.Context, wg *sync.WaitGroup, channel chan  *treactorpb.Bond, plan Plan) {
+func (o *Operator) execute(ctx context.Context, wg *sync.WaitGroup, channel chan *treactorpb.Bond, plan Plan) {
 	defer wg.Done()
-	ctx, span := resource.Tracer.Start(ctx, "Operator [execute]")
+	ctx, span := resource.StartSpan(ctx, resource.GranularityVerbose, "Operator [execute]", trace.WithAttributes(
+		resource.PlanKey.String(plan.String())))
 	defer span.End()
 	plan.Execute(ctx, channel)
 }
@@ -131,62 +189,121 @@ func (o *Operator) String() string {
 	return o.left.String() + "^" + o.right.String()
 }
 
-func CallBondResource(context context.Context, channel chan  *treactorpb.Bond, molecule string) {
-	url := resource.MoleculeUrl(molecule)
-	context = httptrace.WithClientTrace(context, otelhttptrace.NewClientTrace(context))
-	req, _ := http.NewRequestWithContext(context, "GET", url, nil)
-	ra, err := resource.HttpClient.Do(req)
-	if err != nil {
-		fmt.Println(err)
-		return
+// clientTrace adds spans for the phases of the http call (dns, connect, ...) in verbose granularity
+func clientTrace(ctx context.Context) context.Context {
+	if resource.SpanGranularity < resource.GranularityVerbose {
+		return ctx
 	}
-	defer ra.Body.Close()
+	return httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx))
+}
 
-	bodyBytes, err := ioutil.ReadAll(ra.Body)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	var node treactorpb.Node
-	protojson.Unmarshal(bodyBytes, &node)
+// setSeedHeaders sends the seed and the hop path along, the called hop draws its numbers from them
+func setSeedHeaders(ctx context.Context, req *http.Request) {
+	req.Header.Set(SeedHeader, strconv.FormatInt(Seed(ctx), 10))
+	req.Header.Set(PathHeader, Path(ctx))
+}
 
-	var bond = &treactorpb.Bond{
-		Response: nil,
-		Node:     &node,
-	}
-	channel <- bond
+func CallBondResource(context context.Context, channel chan *treactorpb.Bond, molecule string, body []byte) {
+	call(context, channel, resource.MoleculeUrl(molecule), Depth(context)+1, body)
 }
 
-func CallElementResource(context context.Context, channel chan  *treactorpb.Bond, symbol string) {
-	full := symbol
-	symbol = strings.Split(full, ",")[0]
-	var url string
-	if resource.IsLocalMode() {
-		url = fmt.Sprintf("http://localhost:%s%s/atoms/%s?symbol=%s", resource.Port, resource.Base, symbol, full)
-	} else {
-		url = fmt.Sprintf("http://atom-%s%s/atoms/%s?symbol=%s", strings.ToLower(symbol), resource.Base, strings.ToLower(symbol), full)
+func CallElementResource(context context.Context, channel chan *treactorpb.Bond, symbol string, body []byte) {
+	call(context, channel, resource.AtomUrl(symbol), Depth(context), body)
+}
+
+// call gets the node of the bond or atom and sends the bond to the channel, with a body it posts it. A failed call
+// still sends a bond, the error and the kind of fault are in the response.
+func call(ctx context.Context, channel chan *treactorpb.Bond, url string, depth int, body []byte) {
+	ctx = clientTrace(ctx)
+	response := &treactorpb.TReactorResponse{}
+	bond := &treactorpb.Bond{
+		Response: response,
+		Node:     &treactorpb.Node{},
+		Choices:  Choices(ctx),
+	}
+	start := time.Now()
+	defer func() {
+		response.DurationMs = time.Now().Sub(start).Milliseconds()
+		if response.Fault != "" {
+			trace.SpanFromContext(ctx).AddEvent("fault", trace.WithAttributes(
+				resource.FaultKey.String(response.Fault),
+				resource.FaultErrorKey.String(response.Error)))
+		}
+		channel <- bond
+	}()
+
+	method, reader := "GET", io.Reader(nil)
+	if body != nil {
+		method, reader = "POST", bytes.NewReader(body)
 	}
-	context = httptrace.WithClientTrace(context, otelhttptrace.NewClientTrace(context))
-	req, _ := http.NewRequestWithContext(context, "GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		response.Error, response.Fault = err.Error(), "transport"
+		return
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	req.Header.Set(DepthHeader, strconv.Itoa(depth))
+	setSeedHeaders(ctx, req)
 	ra, err := resource.HttpClient.Do(req)
 	if err != nil {
-		fmt.Println(err)
+		response.Error, response.Fault = err.Error(), Fault(err)
+		if response.Fault == "redirects" {
+			response.Redirects = maxRedirects
+		}
 		return
 	}
 	defer ra.Body.Close()
 
+	response.StatusCode = int32(ra.StatusCode)
+	response.StatusMessage = http.StatusText(ra.StatusCode)
+	response.Headers = make(map[string]string, len(ra.Header))
+	for key, values := range ra.Header {
+		for _, value := range values {
+			response.HeaderBytes += int64(len(key) + len(value) + 4)
+		}
+		if !strings.HasPrefix(key, "X-Treactor-Padding-") {
+			response.Headers[key] = strings.Join(values, "|")
+		}
+	}
+	for r := ra.Request; r.Response != nil; r = r.Response.Request {
+		response.Redirects++
+	}
+
 	bodyBytes, err := ioutil.ReadAll(ra.Body)
 	if err != nil {
-		fmt.Println(err)
+		response.Error, response.Fault = err.Error(), Fault(err)
 		return
 	}
-	var node treactorpb.Node
-	protojson.Unmarshal(bodyBytes, &node)
-
-	var bond = &treactorpb.Bond{
-		Response: nil,
-		Node:     &node,
+	if ra.StatusCode >= 400 {
+		response.Error, response.Fault = strings.TrimSpace(ra.Status+" "+string(bodyBytes)), "status"
+		return
+	}
+	if err := protojson.Unmarshal(bodyBytes, bond.Node); err != nil {
+		response.Error, response.Fault = err.Error(), "transport"
 	}
-	channel <- bond
+}
 
+// maxRedirects is the number of redirects the http client follows
+const maxRedirects = 10
+
+// Fault tells the kind of transport error of a call
+func Fault(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNRESET):
+		return "reset"
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "truncated"
+	case strings.Contains(err.Error(), "stopped after"):
+		return "redirects"
+	case strings.Contains(err.Error(), "response headers exceeded"):
+		return "headers"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "refused"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	}
+	return "transport"
 }
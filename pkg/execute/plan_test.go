@@ -1,9 +1,17 @@
 package execute
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/element"
+	"github.com/treactor/treactor-go/pkg/resource"
 )
 
 func TestSuccess(t *testing.T) {
@@ -19,6 +27,8 @@ func TestSuccess(t *testing.T) {
 		{"5[Ur,log:1,xyz:4]", "5s[Ur,log:1,xyz:4]"},
 		{"5[Ur,log:1,xyz:4]^5[Ur,log:1,xyz:4]", "5s[Ur,log:1,xyz:4]^5s[Ur,log:1,xyz:4]"},
 		{"2[5[Ur,log:1,xyz:4]^5[Ur,log:1,xyz:4]],x:1,y:2", "2s[5[Ur,log:1,xyz:4]^5[Ur,log:1,xyz:4]],x:1,y:2"},
+		{"[H],log:5@warning,logsize:1k", "1s[H],log:5@warning,logsize:1k"},
+		{"[[H,cpu:500ms@2x50%]]", "1s[[H,cpu:500ms@2x50%]]"},
 	} {
 		//t.Logf(test.in)
 		plan, err := Parse(test.in)
@@ -34,6 +44,18 @@ func TestSuccess(t *testing.T) {
 
 }
 
+func TestParseBlockValue(t *testing.T) {
+	block, err := ParseBlock("H,log:5@error,logformat:multiline")
+	assert.NoError(t, err)
+	assert.Equal(t, "H", block.Block)
+	assert.Equal(t, map[string]string{"log": "5@error", "logformat": "multiline"}, block.KV)
+}
+
+func TestUnclosedBlock(t *testing.T) {
+	_, err := Parse("[Ur")
+	assert.Error(t, err)
+}
+
 //func TestFail(t *testing.T) {
 //
 //	for _, test := range []struct {
@@ -56,3 +78,144 @@ func TestSuccess(t *testing.T) {
 //	}
 //
 //}
+
+// loadAtoms loads the embedded periodic table up to the max number, the previous table is back after the test
+func loadAtoms(t *testing.T, maxNumber int) {
+	atoms, previous := resource.Atoms, resource.MaxNumber
+	t.Cleanup(func() { resource.Atoms, resource.MaxNumber = atoms, previous })
+	resource.Atoms, _ = element.Load("")
+	resource.MaxNumber = maxNumber
+}
+
+func TestSelection(t *testing.T) {
+	loadAtoms(t, 103)
+
+	for _, test := range []struct {
+		in      string
+		symbols []string
+	}{
+		{"[group:18]", []string{"He", "Ne", "Ar", "Kr", "Xe", "Rn"}},
+		{"p[period:2,property:metalloid]", []string{"B"}},
+		{"[1..3,cpu:10]", []string{"H,cpu:10", "He,cpu:10", "Li,cpu:10"}},
+		{"2p[property:noble_gas],log:1", []string{"He", "Ne", "Ar", "Kr", "Xe", "Rn"}},
+	} {
+		plan, err := Parse(test.in)
+		assert.NoError(t, err, test.in)
+		selection := plan.(*Selection)
+		var symbols []string
+		for _, block := range selection.Blocks {
+			symbols = append(symbols, block.Block)
+		}
+		assert.Equal(t, test.symbols, symbols, test.in)
+		assert.Equal(t, selection.times*len(test.symbols), plan.Calls())
+	}
+
+	plan, err := Parse("2p[group:1]^[Ur]")
+	assert.NoError(t, err)
+	assert.Equal(t, "2p[group:1]^1s[Ur]", plan.String())
+
+	_, err = Parse("[group:42]")
+	assert.Error(t, err)
+	_, err = Parse("[property:plasma]")
+	assert.Error(t, err)
+	_, err = Parse("[0..0]")
+	assert.Error(t, err)
+}
+
+func TestChoice(t *testing.T) {
+	loadAtoms(t, 103)
+	plan, err := Parse("[H]|3[O,cpu:5]^2[C]")
+	assert.NoError(t, err)
+	assert.Equal(t, "1s[H]|3s[O,cpu:5]^2s[C]", plan.String())
+	choice := plan.(*Operator).left.(*Choice)
+	assert.Equal(t, []int{1, 3}, choice.weights)
+	assert.Equal(t, 1, choice.Calls())
+
+	picked := make([]int, 2)
+	for seed := int64(0); seed < 1000; seed++ {
+		ctx := WithSeed(context.Background(), seed)
+		branch := choice.pick(ctx)
+		assert.Equal(t, branch, choice.pick(ctx), "same seed, same branch")
+		picked[branch]++
+	}
+	assert.InDelta(t, 750, picked[1], 60)
+
+	plan, err = Parse("[Ur]|2p[group:18]")
+	assert.NoError(t, err)
+	assert.Equal(t, 6, plan.Calls())
+
+	_, err = Parse("[H]|0[O]")
+	assert.Error(t, err)
+	_, err = Parse("[H]|")
+	assert.Error(t, err)
+}
+
+func TestRandomAtom(t *testing.T) {
+	loadAtoms(t, 10)
+
+	plan, err := Parse("3p[?,cpu:5]")
+	assert.NoError(t, err)
+	assert.Equal(t, "3p[?,cpu:5]", plan.String())
+	assert.True(t, plan.(*Block).isAtom())
+
+	ctx := WithSeed(context.Background(), 42)
+	for repetition := 1; repetition <= 20; repetition++ {
+		atom, ok := randomAtom(ctx, 0, repetition)
+		assert.True(t, ok)
+		assert.True(t, atom.Number >= 1 && atom.Number <= 10, atom.Symbol)
+		again, _ := randomAtom(ctx, 0, repetition)
+		assert.Equal(t, atom.Symbol, again.Symbol)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	ctx := WithSeed(context.Background(), 42)
+	hop := withHop(withHop(ctx, 0, 2, ""), 1, 3, "He")
+	assert.Equal(t, "0.2/1.3.He", Path(hop))
+	assert.Equal(t, Rand(hop, "fail").Int63(), Rand(hop, "fail").Int63())
+	assert.NotEqual(t, Rand(hop, "fail").Int63(), Rand(hop, "decay").Int63())
+	assert.NotEqual(t, Rand(hop, "fail").Int63(), Rand(withHop(ctx, 0, 2, "He"), "fail").Int63())
+	assert.NotEqual(t, Rand(hop, "fail").Int63(), Rand(WithSeed(hop, 43), "fail").Int63())
+
+	r := httptest.NewRequest("GET", "/treact/reactions?molecule=[H]&seed=7", nil)
+	assert.Equal(t, int64(7), SeedFromRequest(r))
+	r.Header.Set(SeedHeader, "8")
+	r.Header.Set(PathHeader, "0.1")
+	assert.Equal(t, int64(8), SeedFromRequest(r))
+	assert.Equal(t, "0.1", PathFromRequest(r))
+}
+
+func TestBlob(t *testing.T) {
+	spec, err := ParseBlob("payload", "2k")
+	assert.NoError(t, err)
+	assert.Equal(t, &BlobSpec{Size: 2048, Mode: "random"}, spec)
+	ctx := WithSeed(context.Background(), 42)
+	blob := spec.Generate(ctx, "payload")
+	assert.Len(t, blob, 2048)
+	assert.Equal(t, blob, spec.Generate(ctx, "payload"))
+	assert.NotEqual(t, blob, spec.Generate(WithSeed(ctx, 43), "payload"))
+
+	spec, err = ParseBlob("body", "1k@text")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(spec.Generate(ctx, "body")), "The quick brown fox"))
+
+	_, err = ParseBlob("body", "1g")
+	assert.Error(t, err)
+	_, err = ParseBlob("body", "1k@zip")
+	assert.Error(t, err)
+	_, err = ParseBlob("body", "@text")
+	assert.Error(t, err)
+
+	// a malformed body is ignored, the call is a GET
+	defer func(logger resource.RLogger) { resource.Logger = logger }(resource.Logger)
+	resource.Logger = resource.NewLogger("text", ioutil.Discard)
+	assert.Nil(t, requestBody(ctx, "@text"))
+}
+
+func TestCallMalformedUrl(t *testing.T) {
+	channel := make(chan *treactorpb.Bond, 1)
+	call(context.Background(), channel, "http://[::1/treact/atoms/h", 0, nil)
+	bond := <-channel
+	assert.Equal(t, "transport", bond.Response.Fault)
+	assert.NotEmpty(t, bond.Response.Error)
+}
@@ -0,0 +1,116 @@
+package execute
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// SeedHeader carries the seed of the reaction to every hop and back to the caller, a reaction run again with
+	// the seed makes the same choices, failures and decays
+	SeedHeader = "X-Treactor-Seed"
+	// PathHeader tells the hop where it is in the reaction, like 0.1/2.3.He: the block index and repetition of
+	// every bond on the way, and the symbol for an atom
+	PathHeader = "X-Treactor-Path"
+)
+
+type seedKey struct{}
+
+type pathKey struct{}
+
+var (
+	seedMu   sync.Mutex
+	seedRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// NewSeed returns a random seed for a reaction that didn't get one
+func NewSeed() int64 {
+	seedMu.Lock()
+	defer seedMu.Unlock()
+	return seedRand.Int63()
+}
+
+func WithSeed(ctx context.Context, seed int64) context.Context {
+	return context.WithValue(ctx, seedKey{}, seed)
+}
+
+func Seed(ctx context.Context) int64 {
+	seed, _ := ctx.Value(seedKey{}).(int64)
+	return seed
+}
+
+// SeedFromRequest reads the seed sent by the calling hop, or else the seed query parameter. The entry point of
+// a reaction gets a new seed when both are missing or malformed.
+func SeedFromRequest(r *http.Request) int64 {
+	for _, value := range []string{r.Header.Get(SeedHeader), r.URL.Query().Get("seed")} {
+		if seed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return seed
+		}
+	}
+	return NewSeed()
+}
+
+func WithPath(ctx context.Context, path string) context.Context {
+	return context.WithValue(ctx, pathKey{}, path)
+}
+
+// Path is the hop path of the bond or atom, empty for the reaction itself
+func Path(ctx context.Context) string {
+	path, _ := ctx.Value(pathKey{}).(string)
+	return path
+}
+
+// PathFromRequest reads the hop path set by the caller
+func PathFromRequest(r *http.Request) string {
+	return r.Header.Get(PathHeader)
+}
+
+// withHop extends the hop path for a call, with the index and repetition of the block
+func withHop(ctx context.Context, index int, repetition int, symbol string) context.Context {
+	hop := strconv.Itoa(index) + "." + strconv.Itoa(repetition)
+	if symbol != "" {
+		hop += "." + symbol
+	}
+	if path := Path(ctx); path != "" {
+		hop = path + "/" + hop
+	}
+	return WithPath(ctx, hop)
+}
+
+// Rand returns the random source for a decision, like fail or choice. The seed of the reaction is mixed with
+// the hop path, the decision and the values identifying it (block index, repetition). Every hop draws its own
+// numbers, parallel blocks make the same choices whatever order they run in.
+func Rand(ctx context.Context, decision string, values ...int) *rand.Rand {
+	h := fnv.New64a()
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(Seed(ctx)))
+	h.Write(b)
+	h.Write([]byte(Path(ctx)))
+	h.Write([]byte{0})
+	h.Write([]byte(decision))
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(b, uint64(v))
+		h.Write(b)
+	}
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+type choicesKey struct{}
+
+// withChoice adds a choice made by the plan, the bonds executed with the context record it
+func withChoice(ctx context.Context, choice string) context.Context {
+	choices := append(append([]string(nil), Choices(ctx)...), choice)
+	return context.WithValue(ctx, choicesKey{}, choices)
+}
+
+// Choices are the weighted branches and random atoms chosen for the bond
+func Choices(ctx context.Context) []string {
+	choices, _ := ctx.Value(choicesKey{}).([]string)
+	return choices
+}
@@ -0,0 +1,108 @@
+package execute
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/element"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/context"
+)
+
+// Selection is a selector like [group:18] or [1..10], expanded into a block per matching atom. The blocks are
+// called in the mode of the selection, every repetition calls all of them.
+type Selection struct {
+	index    int
+	times    int
+	mode     string
+	Selector string
+	Blocks   []*Block
+	KV       map[string]string
+}
+
+// isSelector tells if the content of a block selects atoms instead of naming one
+func isSelector(content string) bool {
+	first := strings.Split(content, ",")[0]
+	key := strings.SplitN(first, ":", 2)[0]
+	return element.IsRange(first) || (strings.Contains(first, ":") && element.IsQueryKey(key))
+}
+
+// newSelection expands the selector into the blocks, KVs of the content that aren't criteria go to every atom.
+// Only the atoms up to TREACTOR_MAX_NUMBER are selected, the numbers above are not atom services.
+func newSelection(content string) (*Selection, error) {
+	criteria := make(map[string]string)
+	var atomKV []string
+	for i, item := range strings.Split(content, ",") {
+		if i == 0 && element.IsRange(item) {
+			criteria["number"] = item
+			continue
+		}
+		kv := strings.SplitN(item, ":", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, errors.New("Selector needs key:value")
+		}
+		if element.IsQueryKey(kv[0]) {
+			criteria[kv[0]] = kv[1]
+		} else {
+			atomKV = append(atomKV, item)
+		}
+	}
+	query, err := element.ParseQuery(criteria)
+	if err != nil {
+		return nil, err
+	}
+	selection := &Selection{Selector: content}
+	for _, atom := range resource.Atoms.Select(query) {
+		if int(atom.Number) > resource.MaxNumber {
+			continue
+		}
+		selection.Blocks = append(selection.Blocks, &Block{
+			times: 1,
+			mode:  "s",
+			Block: strings.Join(append([]string{atom.Symbol}, atomKV...), ","),
+			KV:    make(map[string]string),
+		})
+	}
+	if len(selection.Blocks) == 0 {
+		return nil, errors.New("Selector matches no atoms")
+	}
+	return selection, nil
+}
+
+func (o *Selection) Execute(ctx context.Context, channel chan *treactorpb.Bond) {
+	ctx, span := resource.StartSpan(ctx, resource.GranularityPlan, "Execute Selection", trace.WithAttributes(
+		resource.PlanKey.String(o.String()),
+		resource.BlockIndexKey.Int(o.index),
+		resource.ModeKey.String(o.mode),
+		resource.TimesKey.Int(o.times),
+		resource.SelectorKey.String(o.Selector)))
+	defer span.End()
+	span.SetAttributes(resource.KVAttributes(o.KV)...)
+	if o.KV["log"] != "" {
+		resource.EmitLogs(ctx, o.KV)
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(o.Calls())
+	for i := 1; i <= o.times; i++ {
+		for _, block := range o.Blocks {
+			if o.mode == "p" {
+				go block.callElement(ctx, &wg, channel, i)
+			} else {
+				block.callElement(ctx, &wg, channel, i)
+			}
+		}
+	}
+	wg.Wait()
+}
+
+func (o *Selection) Calls() int {
+	return o.times * len(o.Blocks)
+}
+
+func (o *Selection) String() string {
+	return strconv.Itoa(o.times) + o.mode + "[" + o.Selector + "]" + kvString(o.KV)
+}
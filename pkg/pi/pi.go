@@ -1,6 +1,12 @@
 package pi
 
-import "math"
+import (
+	"context"
+	"math"
+)
+
+// chunkSize is the number of terms a worker of Chunked sums at a time
+const chunkSize = 100000
 
 func Parallel(n int) float64 {
 	ch := make(chan float64)
@@ -34,6 +40,60 @@ func Single(n int) float64 {
 	return f
 }
 
+// Partial sums the terms from up to, not including, to of the Leibniz series. It's the compute kernel of the cpu
+// action, the work shows up as pi frames in a CPU profile.
+func Partial(from, to int) float64 {
+	f := 0.0
+	for k := from; k < to; k++ {
+		f += termFunc(float64(k))
+	}
+	return f
+}
 
+// Series sums the terms 0 to n in the calling goroutine, a chunk of terms at a time. It stops when the context is
+// done, it returns the sum and the number of terms summed.
+func Series(ctx context.Context, n int) (float64, int) {
+	f := 0.0
+	k := 0
+	for k <= n && ctx.Err() == nil {
+		to := chunkEnd(k, n)
+		f += Partial(k, to)
+		k = to
+	}
+	return f, k
+}
 
+// Chunked sums the terms 0 to n with a pool of workers, every worker takes the next chunk of terms till none are left.
+// It stops handing out chunks when the context is done, it returns the sum and the number of terms summed.
+func Chunked(ctx context.Context, n int, workers int) (float64, int) {
+	chunks := make(chan int)
+	sums := make(chan float64, workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			f := 0.0
+			for from := range chunks {
+				f += Partial(from, chunkEnd(from, n))
+			}
+			sums <- f
+		}()
+	}
+	terms := 0
+	for from := 0; from <= n && ctx.Err() == nil; from += chunkSize {
+		chunks <- from
+		terms = chunkEnd(from, n)
+	}
+	close(chunks)
+	f := 0.0
+	for w := 0; w < workers; w++ {
+		f += <-sums
+	}
+	return f, terms
+}
 
+// chunkEnd is the end, not included, of the chunk of terms starting at from
+func chunkEnd(from int, n int) int {
+	if from+chunkSize > n+1 {
+		return n + 1
+	}
+	return from + chunkSize
+}
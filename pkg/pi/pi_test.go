@@ -1,6 +1,10 @@
 package pi
 
-import "testing"
+import (
+	"context"
+	"math"
+	"testing"
+)
 
 func TestPiParallel(t *testing.T) {
 	t.Logf("%f", Parallel(1*1000*1000))
@@ -16,3 +20,36 @@ func TestPiSingle(t *testing.T) {
 	t.Logf("%f", Single(64*1000*1000))
 	t.Logf("%f", Single(128*1000*1000))
 }
+
+func TestPiPartial(t *testing.T) {
+	if math.Abs(Partial(0, 1000)+Partial(1000, 2000)-Single(1999)) > 1e-12 {
+		t.Errorf("partial sums don't add up to the series")
+	}
+}
+
+func TestPiChunked(t *testing.T) {
+	for _, workers := range []int{1, 3, 8} {
+		f, terms := Chunked(context.Background(), 1234567, workers)
+		if math.Abs(f-Single(1234567)) > 1e-9 || terms != 1234568 {
+			t.Errorf("chunked with %d workers differs from single", workers)
+		}
+	}
+}
+
+func TestPiSeries(t *testing.T) {
+	f, terms := Series(context.Background(), 1234567)
+	if math.Abs(f-Single(1234567)) > 1e-9 || terms != 1234568 {
+		t.Errorf("series differs from single")
+	}
+}
+
+func TestPiCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, terms := Series(ctx, 1000*1000*1000); terms != 0 {
+		t.Errorf("cancelled series summed %d terms", terms)
+	}
+	if _, terms := Chunked(ctx, 1000*1000*1000, 4); terms != 0 {
+		t.Errorf("cancelled chunked summed %d terms", terms)
+	}
+}
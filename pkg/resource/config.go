@@ -2,8 +2,11 @@ package resource
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var (
@@ -12,7 +15,18 @@ var (
 	AppName    string
 	Framework  string
 
-	OtlpEndpoint string
+	ServiceNamespace string
+	ServiceInstance  string
+
+	OtlpEndpoint     string
+	TracesExporter   string
+	TraceStoreSize   int
+	LogsExporter     string
+	LogExportDelayMs int
+
+	CollectorTarget   string
+	CollectorGrpcPort string
+	CollectorHttpPort string
 
 	Mode             string
 	debug            string
@@ -21,10 +35,23 @@ var (
 	MaxNumber        int
 	MaxBond          int
 	tracePropagation string
-	logMethod        string
+	LogMethod        string
+	GcpProject       string
+	ElementsFile     string
+	ElementBehavior  bool
+	DiskDir          string
+	AllowDestructive bool
+	AdminPort        string
+	AdminToken       string
 	Number           int32
 	Module           string
 	Component        string
+
+	StartupDelay      time.Duration
+	DrainDelay        time.Duration
+	UnreadyAfter      int64
+	LivenessFlap      string
+	ReadyDependencies []string
 )
 
 func getEnv(key, fallback string) string {
@@ -36,8 +63,10 @@ func getEnv(key, fallback string) string {
 func Configure() {
 	// General Settings
 	Port = getEnv("PORT", "3330")
-	AppName = getEnv("SERVICE_NAME", "treactor-app")
+	AppName = getEnv("OTEL_SERVICE_NAME", getEnv("SERVICE_NAME", "treactor-app"))
 	AppVersion = getEnv("SERVICE_VERSION", "0.0")
+	ServiceNamespace = getEnv("SERVICE_NAMESPACE", "treactor")
+	ServiceInstance = getEnv("SERVICE_INSTANCE_ID", "")
 	Framework = "golang"
 	// Reactor Specific Settings
 	Mode = getEnv("TREACTOR_MODE", "local")
@@ -50,11 +79,64 @@ func Configure() {
 	MaxBond, _ = strconv.Atoi(getEnv("TREACTOR_MAX_BOND", "5"))
 	n, _ := strconv.Atoi(getEnv("TREACTOR_NUMBER", "0"))
 	Number = int32(n)
+	ElementsFile = getEnv("TREACTOR_ELEMENTS_FILE", "")
+	ElementBehavior = getEnv("TREACTOR_BEHAVIOR", "none") == "element"
+	profile = getEnv("TREACTOR_PROFILE", "0")
+	DiskDir = getEnv("TREACTOR_DISK_DIR", os.TempDir())
+	AllowDestructive = getEnv("TREACTOR_ALLOW_DESTRUCTIVE", "0") == "1"
+	AdminPort = getEnv("TREACTOR_ADMIN_PORT", "3331")
+	AdminToken = getEnv("TREACTOR_ADMIN_TOKEN", "")
+	configureSettings()
 
-	OtlpEndpoint = getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	// Probe Settings
+	StartupDelay, _ = ParseDuration(getEnv("TREACTOR_STARTUP_DELAY", "0"))
+	DrainDelay, _ = ParseDuration(getEnv("TREACTOR_DRAIN_DELAY", "0"))
+	UnreadyAfter, _ = strconv.ParseInt(getEnv("TREACTOR_UNREADY_AFTER", "0"), 10, 64)
+	LivenessFlap = getEnv("TREACTOR_LIVENESS_FLAP", "")
+	if dependencies := getEnv("TREACTOR_READY_DEPENDENCIES", ""); dependencies != "" {
+		ReadyDependencies = strings.Split(dependencies, ",")
+	}
+
+	OtlpEndpoint = getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317")
+	TracesExporter = getEnv("OTEL_TRACES_EXPORTER", "otlp")
+	LogsExporter = getEnv("OTEL_LOGS_EXPORTER", "none")
+	LogExportDelayMs, _ = strconv.Atoi(getEnv("OTEL_BLRP_SCHEDULE_DELAY", "1000"))
+	if LogExportDelayMs <= 0 {
+		LogExportDelayMs = 1000
+	}
+	if IsLocalMode() {
+		TraceStoreSize, _ = strconv.Atoi(getEnv("TREACTOR_TRACE_STORE", "100"))
+	} else if IsCollectorMode() {
+		TraceStoreSize, _ = strconv.Atoi(getEnv("TREACTOR_TRACE_STORE", "1000"))
+		if TraceStoreSize <= 0 {
+			TraceStoreSize = 1000
+		}
+	} else {
+		TraceStoreSize, _ = strconv.Atoi(getEnv("TREACTOR_TRACE_STORE", "0"))
+	}
+
+	// Collector Settings
+	CollectorTarget = getEnv("TREACTOR_COLLECTOR_TARGET", fmt.Sprintf("http://localhost:%s", Port))
+	CollectorGrpcPort = getEnv("TREACTOR_COLLECTOR_GRPC_PORT", "4317")
+	CollectorHttpPort = getEnv("TREACTOR_COLLECTOR_HTTP_PORT", "4318")
 
 	tracePropagation = getEnv("TREACTOR_TRACE_PROPAGATION", "w3c")
-	logMethod = os.Getenv("TREACTOR_LOG_METHOD")
+	SpanGranularity = ParseGranularity(getEnv("TREACTOR_TRACE_GRANULARITY", "verbose"))
+	LogMethod = getEnv("TREACTOR_LOG_METHOD", "gcp")
+	GcpProject = firstNonEmpty(os.Getenv("TREACTOR_GCP_PROJECT"), os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		os.Getenv("GCP_PROJECT"), os.Getenv("GCLOUD_PROJECT"))
+}
+
+// ParseDuration parses a duration like 500ms or 2s, a plain number is in milliseconds
+func ParseDuration(value string) (time.Duration, error) {
+	if ms, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(ms * float64(time.Millisecond)), nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("malformed duration %s", value)
+	}
+	return duration, nil
 }
 
 func IsLocalMode() bool {
@@ -65,13 +147,23 @@ func IsKubernetesMode() bool {
 	return "cluster" == Mode
 }
 
+func IsCollectorMode() bool {
+	return "collector" == Mode
+}
+
+// IsProfiling tells if the pprof endpoints are served, on /debug/pprof
+func IsProfiling() bool {
+	return "1" == profile
+}
+
 func MoleculeUrl(molecule string) string {
+	molecule = url.QueryEscape(molecule)
 	if Mode == "cluster" {
 		if Module == "bond" {
 			if Component == "n" {
 				return fmt.Sprintf("http://bond-n/treact/bonds/n?molecule=%s&execute=1", molecule)
 			}
-			next,_ := strconv.Atoi(Component)
+			next, _ := strconv.Atoi(Component)
 			next++
 			if next > MaxBond {
 				return fmt.Sprintf("http://bond-n/treact/bonds/n?molecule=%s&execute=1", molecule)
@@ -84,6 +176,17 @@ func MoleculeUrl(molecule string) string {
 	}
 }
 
+// AtomUrl is the url of the atom of the symbol, the symbol can have KVs like H,sleep:100. Only in cluster mode the
+// atoms are services of their own, the collector runs them itself like in local mode.
+func AtomUrl(symbol string) string {
+	full := url.QueryEscape(symbol)
+	atom := strings.ToLower(strings.Split(symbol, ",")[0])
+	if IsKubernetesMode() {
+		return fmt.Sprintf("http://atom-%s%s/atoms/%s?symbol=%s", atom, Base, atom, full)
+	}
+	return fmt.Sprintf("http://localhost:%s%s/atoms/%s?symbol=%s", Port, Base, atom, full)
+}
+
 func TracePropagation() {
 	//switch traceInternal {
 	//case "b3":
@@ -0,0 +1,20 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomUrl(t *testing.T) {
+	defer func(mode, port, base string) { Mode, Port, Base = mode, port, base }(Mode, Port, Base)
+	Port, Base = "3330", "/treact"
+	for mode, expected := range map[string]string{
+		"local":     "http://localhost:3330/treact/atoms/h?symbol=H%2Csleep%3A100",
+		"collector": "http://localhost:3330/treact/atoms/h?symbol=H%2Csleep%3A100",
+		"cluster":   "http://atom-h/treact/atoms/h?symbol=H%2Csleep%3A100",
+	} {
+		Mode = mode
+		assert.Equal(t, expected, AtomUrl("H,sleep:100"), mode)
+	}
+}
@@ -0,0 +1,162 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+var (
+	// Locations of the files read by the detectors, variables so they can be pointed elsewhere in tests
+	cgroupFile        = "/proc/self/cgroup"
+	mountInfoFile     = "/proc/self/mountinfo"
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	podInfoDir        = "/etc/podinfo"
+
+	containerIdPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+)
+
+// k8sNodeNameKey is not (yet) in the semconv package of this OpenTelemetry version
+const k8sNodeNameKey = attribute.Key("k8s.node.name")
+
+// detectResource builds the resource of all the telemetry. Later detectors win, so the standard
+// OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME overrule what treactor detected itself.
+func detectResource(ctx context.Context) (*resource.Resource, error) {
+	return resource.Detect(ctx,
+		resource.TelemetrySDK{},
+		resource.Host{},
+		processDetector{},
+		containerDetector{},
+		kubernetesDetector{},
+		serviceDetector{},
+		resource.FromEnv{},
+		serviceNameDetector{},
+	)
+}
+
+// serviceDetector describes the treactor service, the instance is the pod or else host and pid
+type serviceDetector struct{}
+
+func (serviceDetector) Detect(context.Context) (*resource.Resource, error) {
+	instance := ServiceInstance
+	if instance == "" {
+		instance = readPodInfo("K8S_POD_NAME", "POD_NAME", "name")
+	}
+	if instance == "" {
+		hostname, _ := os.Hostname()
+		instance = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	return resource.NewWithAttributes(
+		semconv.ServiceNameKey.String(AppName),
+		semconv.ServiceVersionKey.String(AppVersion),
+		semconv.ServiceNamespaceKey.String(ServiceNamespace),
+		semconv.ServiceInstanceIDKey.String(instance),
+	), nil
+}
+
+// serviceNameDetector applies OTEL_SERVICE_NAME, it takes precedence over service.name in OTEL_RESOURCE_ATTRIBUTES
+type serviceNameDetector struct{}
+
+func (serviceNameDetector) Detect(context.Context) (*resource.Resource, error) {
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		return resource.NewWithAttributes(semconv.ServiceNameKey.String(name)), nil
+	}
+	return resource.Empty(), nil
+}
+
+type processDetector struct{}
+
+func (processDetector) Detect(context.Context) (*resource.Resource, error) {
+	attributes := []attribute.KeyValue{
+		semconv.ProcessPIDKey.Int(os.Getpid()),
+		semconv.ProcessCommandLineKey.String(strings.Join(os.Args, " ")),
+	}
+	if executable, err := os.Executable(); err == nil {
+		attributes = append(attributes,
+			semconv.ProcessExecutableNameKey.String(filepath.Base(executable)),
+			semconv.ProcessExecutablePathKey.String(executable))
+	}
+	return resource.NewWithAttributes(attributes...), nil
+}
+
+// containerDetector finds the container id in the cgroup (v1) or mount info (v2) of the process
+type containerDetector struct{}
+
+func (containerDetector) Detect(context.Context) (*resource.Resource, error) {
+	id := containerId()
+	if id == "" {
+		return resource.Empty(), nil
+	}
+	return resource.NewWithAttributes(semconv.ContainerIDKey.String(id)), nil
+}
+
+func containerId() string {
+	if content, err := ioutil.ReadFile(cgroupFile); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			if id := containerIdPattern.FindString(line); id != "" {
+				return id
+			}
+		}
+	}
+	if content, err := ioutil.ReadFile(mountInfoFile); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			if !strings.Contains(line, "/containers/") {
+				continue
+			}
+			if id := containerIdPattern.FindString(line); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// kubernetesDetector reads the pod, namespace and node from the downward API, either exposed as
+// environment variables or as files in a downward API volume mounted at /etc/podinfo
+type kubernetesDetector struct{}
+
+func (kubernetesDetector) Detect(context.Context) (*resource.Resource, error) {
+	var attributes []attribute.KeyValue
+	add := func(key attribute.Key, value string) {
+		if value != "" {
+			attributes = append(attributes, key.String(value))
+		}
+	}
+	add(semconv.K8SPodNameKey, readPodInfo("K8S_POD_NAME", "POD_NAME", "name"))
+	add(semconv.K8SPodUIDKey, readPodInfo("K8S_POD_UID", "POD_UID", "uid"))
+	add(semconv.K8SNamespaceNameKey, firstNonEmpty(
+		readPodInfo("K8S_NAMESPACE", "POD_NAMESPACE", "namespace"),
+		readFile(filepath.Join(serviceAccountDir, "namespace"))))
+	add(k8sNodeNameKey, readPodInfo("K8S_NODE_NAME", "NODE_NAME", "nodename"))
+	return resource.NewWithAttributes(attributes...), nil
+}
+
+// readPodInfo returns the first of the environment variables or the file in the downward API volume
+func readPodInfo(env string, alternativeEnv string, file string) string {
+	return firstNonEmpty(os.Getenv(env), os.Getenv(alternativeEnv), readFile(filepath.Join(podInfoDir, file)))
+}
+
+func readFile(path string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
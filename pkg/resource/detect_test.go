@@ -0,0 +1,68 @@
+package resource
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func attributes(rs *resource.Resource) map[attribute.Key]string {
+	m := make(map[attribute.Key]string)
+	for _, kv := range rs.Attributes() {
+		m[kv.Key] = kv.Value.Emit()
+	}
+	return m
+}
+
+func TestDetectResource(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "detect")
+	defer os.RemoveAll(dir)
+	id := "2a3d0a7f8c1b4e5d6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6"
+	ioutil.WriteFile(filepath.Join(dir, "cgroup"), []byte("12:pids:/kubepods/besteffort/pod1234/"+id+"\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "namespace"), []byte("chemistry\n"), 0644)
+	cgroup, mountInfo, serviceAccount, podInfo := cgroupFile, mountInfoFile, serviceAccountDir, podInfoDir
+	name, version, namespace := AppName, AppVersion, ServiceNamespace
+	defer func() {
+		cgroupFile, mountInfoFile, serviceAccountDir, podInfoDir = cgroup, mountInfo, serviceAccount, podInfo
+		AppName, AppVersion, ServiceNamespace = name, version, namespace
+	}()
+	cgroupFile = filepath.Join(dir, "cgroup")
+	mountInfoFile = filepath.Join(dir, "mountinfo")
+	serviceAccountDir = dir
+	podInfoDir = dir
+
+	AppName = "atom-h"
+	AppVersion = "1.0"
+	ServiceNamespace = "treactor"
+	os.Setenv("K8S_POD_NAME", "atom-h-5d9f7")
+	os.Setenv("NODE_NAME", "node-1")
+	os.Setenv("OTEL_RESOURCE_ATTRIBUTES", "service.version=2.0,deployment.environment=test")
+	os.Setenv("OTEL_SERVICE_NAME", "hydrogen")
+	defer func() {
+		os.Unsetenv("K8S_POD_NAME")
+		os.Unsetenv("NODE_NAME")
+		os.Unsetenv("OTEL_RESOURCE_ATTRIBUTES")
+		os.Unsetenv("OTEL_SERVICE_NAME")
+	}()
+
+	rs, err := detectResource(context.Background())
+	assert.NoError(t, err)
+	a := attributes(rs)
+	assert.Equal(t, "hydrogen", a["service.name"])
+	assert.Equal(t, "2.0", a["service.version"])
+	assert.Equal(t, "treactor", a["service.namespace"])
+	assert.Equal(t, "atom-h-5d9f7", a["service.instance.id"])
+	assert.Equal(t, "test", a["deployment.environment"])
+	assert.Equal(t, id, a["container.id"])
+	assert.Equal(t, "atom-h-5d9f7", a["k8s.pod.name"])
+	assert.Equal(t, "chemistry", a["k8s.namespace.name"])
+	assert.Equal(t, "node-1", a["k8s.node.name"])
+	assert.NotEmpty(t, a["host.name"])
+	assert.NotEmpty(t, a["process.pid"])
+}
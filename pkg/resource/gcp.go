@@ -0,0 +1,54 @@
+package resource
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// metadataTimeout keeps the startup fast when treactor doesn't run on Google Cloud
+const metadataTimeout = 2 * time.Second
+
+// productNameFile is a variable so tests can point it elsewhere
+var productNameFile = "/sys/class/dmi/id/product_name"
+
+// metadataHost is the GCE metadata server, GCE_METADATA_HOST overrules it like in the Google client libraries
+func metadataHost() string {
+	return getEnv("GCE_METADATA_HOST", "metadata.google.internal")
+}
+
+// detectGcpProject asks the metadata server for the project id, empty when there is no metadata server
+func detectGcpProject(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", "http://"+metadataHost()+"/computeMetadata/v1/project/project-id", nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Metadata-Flavor") != "Google" {
+		return ""
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// onGcp guesses if treactor runs on Google Cloud, to avoid waiting for a metadata server that isn't there
+func onGcp() bool {
+	if os.Getenv("GCE_METADATA_HOST") != "" || os.Getenv("K_SERVICE") != "" || os.Getenv("FUNCTION_TARGET") != "" {
+		return true
+	}
+	product := readFile(productNameFile)
+	return strings.HasPrefix(product, "Google")
+}
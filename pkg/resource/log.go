@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"go.opentelemetry.io/otel/trace"
+	"io"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 )
 
@@ -21,22 +23,13 @@ type RLogger interface {
 	Flush()
 }
 
-type rPayLoad struct {
-	EventTime      string          `json:"eventTime,omitempty"`
-	ServiceContext rServiceContext `json:"serviceContext,omitempty"`
-	Message        string          `json:"message,omitempty"`
-	Context        rContext        `json:"context,omitempty"`
-}
-
-type rServiceContext struct {
-	Service string `json:"service,omitempty"`
-	Version string `json:"version,omitempty"`
-}
+// https://cloud.google.com/error-reporting/docs/formatting-error-messages
+const reportedErrorEventType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
 
 type rContext struct {
-	HttpRequest    rHttpRequest    `json:"httpRequest,omitempty"`
-	User           string          `json:"user,omitempty"`
-	ReportLocation rReportLocation `json:"reportLocation,omitempty"`
+	HttpRequest    *rHttpRequest    `json:"httpRequest,omitempty"`
+	User           string           `json:"user,omitempty"`
+	ReportLocation *rReportLocation `json:"reportLocation,omitempty"`
 }
 
 type rHttpRequest struct {
@@ -54,7 +47,6 @@ type rReportLocation struct {
 	FunctionName string `json:"functionName,omitempty"`
 }
 
-
 // https://cloud.google.com/logging/docs/agent/configuration#special-fields
 type SLabel struct {
 	LoggerName string `json:"loggerName,omitempty"`
@@ -80,61 +72,151 @@ type SEntry struct {
 	SpanId       string `json:"logging.googleapis.com/spanId,omitempty"`
 	Trace        string `json:"logging.googleapis.com/trace,omitempty"`
 	TraceSampled bool   `json:"logging.googleapis.com/trace_sampled,omitempty"`
+	InsertId     string `json:"logging.googleapis.com/insertId,omitempty"`
+
+	// Error Reporting
+	Type    string    `json:"@type,omitempty"`
+	Context *rContext `json:"context,omitempty"`
 }
 
-type SLogger struct {
-	projectId string
+// logRecord is the format independent log line
+type logRecord struct {
+	time     time.Time
+	severity string
+	message  string
+	span     trace.SpanContext
+
+	// errors only
+	insertId string
+	request  *rHttpRequest
+	location *rReportLocation
+	stack    string
 }
 
-func NewSLogger(projectId string) *SLogger {
-	return &SLogger{
-		projectId: projectId,
-	}
+// baseLogger implements RLogger, the format turns a record into a line
+type baseLogger struct {
+	mu     sync.Mutex
+	out    io.Writer
+	format func(record *logRecord) ([]byte, error)
 }
 
-func (l *SLogger) InfoF(ctx context.Context, format string, a ...interface{}) {
+func (l *baseLogger) InfoF(ctx context.Context, format string, a ...interface{}) {
 	l.log(ctx, "INFO", fmt.Sprintf(format, a...))
 }
 
-func (l *SLogger) Info(ctx context.Context, message string) {
+func (l *baseLogger) Info(ctx context.Context, message string) {
 	l.log(ctx, "INFO", message)
 }
 
-func (l *SLogger) WarningF(ctx context.Context, format string, a ...interface{}) {
+func (l *baseLogger) WarningF(ctx context.Context, format string, a ...interface{}) {
 	l.log(ctx, "WARNING", fmt.Sprintf(format, a...))
 }
 
-func (l *SLogger) Warning(ctx context.Context, message string) {
+func (l *baseLogger) Warning(ctx context.Context, message string) {
 	l.log(ctx, "WARNING", message)
 }
 
-func (l *SLogger) Error(ctx context.Context, r *http.Request, message string) string {
-	l.log(ctx, "ERROR", message)
-	return ""
+func (l *baseLogger) Error(ctx context.Context, r *http.Request, message string) string {
+	return l.logError(ctx, r, message)
 }
 
-func (l *SLogger) ErrorErr(ctx context.Context, r *http.Request, message string, err error) string {
-	l.log(ctx, "ERROR", fmt.Sprintf("%s: %s", message, err.Error()))
-	return ""
+func (l *baseLogger) ErrorErr(ctx context.Context, r *http.Request, message string, err error) string {
+	return l.logError(ctx, r, fmt.Sprintf("%s: %s", message, err.Error()))
 }
 
-func (l *SLogger) ErrorF(ctx context.Context, r *http.Request, format string, a ...interface{}) string {
-	l.log(ctx, "ERROR", fmt.Sprintf(format, a...))
-	return ""
+func (l *baseLogger) ErrorF(ctx context.Context, r *http.Request, format string, a ...interface{}) string {
+	return l.logError(ctx, r, fmt.Sprintf(format, a...))
 }
 
-func (l *SLogger) Flush() {
-	panic("implement me")
+// Flush waits till the records buffered for export are sent, the output itself isn't buffered
+func (l *baseLogger) Flush() {
+	if LogExporter != nil {
+		LogExporter.Flush()
+	}
+}
+
+func (l *baseLogger) log(ctx context.Context, severity string, message string) {
+	if !logged(severity) {
+		return
+	}
+	l.write(newLogRecord(ctx, severity, message))
+}
+
+// logError writes an error entry with the request, location and stack trace, it returns the insert id
+func (l *baseLogger) logError(ctx context.Context, r *http.Request, message string) string {
+	record := newLogRecord(ctx, "ERROR", message)
+	record.insertId = newInsertId()
+	record.request = requestContext(r)
+	record.stack, record.location = stackTrace()
+	l.write(record)
+	return record.insertId
 }
 
-func (l *SLogger) log(ctx context.Context, severity string, message string) {
-	now := time.Now()
+func newLogRecord(ctx context.Context, severity string, message string) *logRecord {
+	return &logRecord{
+		time:     time.Now(),
+		severity: severity,
+		message:  message,
+		span:     trace.SpanFromContext(ctx).SpanContext(),
+	}
+}
+
+func (l *baseLogger) write(record *logRecord) {
+	b, err := l.format(record)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	l.mu.Lock()
+	l.out.Write(b)
+	l.out.Write([]byte("\n"))
+	l.mu.Unlock()
+	if LogExporter != nil {
+		LogExporter.Export(record)
+	}
+}
+
+// NewLogger creates the logger for the format: gcp (Google Cloud Logging JSON), ecs (Elastic Common Schema),
+// logfmt, otel (OpenTelemetry log data model JSON) or text. Unknown formats fall back to gcp.
+func NewLogger(method string, out io.Writer) RLogger {
+	switch method {
+	case "ecs":
+		return NewECSLogger(out)
+	case "logfmt":
+		return NewLogfmtLogger(out)
+	case "otel":
+		return NewOTelLogger(out)
+	case "text":
+		return NewTextLogger(out)
+	default:
+		l := NewSLogger(GcpProject)
+		l.out = out
+		return l
+	}
+}
+
+// SLogger writes the structured JSON understood by Google Cloud Logging
+type SLogger struct {
+	baseLogger
+	projectId string
+}
+
+func NewSLogger(projectId string) *SLogger {
+	l := &SLogger{
+		projectId: projectId,
+	}
+	l.out = os.Stdout
+	l.format = l.formatEntry
+	return l
+}
+
+func (l *SLogger) formatEntry(record *logRecord) ([]byte, error) {
 	entry := &SEntry{
-		Message:  message,
-		Severity: severity,
+		Message:  record.message,
+		Severity: record.severity,
 		Timestamp: &STimestamp{
-			Seconds: now.Unix(),
-			Nanos:   now.Nanosecond(),
+			Seconds: record.time.Unix(),
+			Nanos:   record.time.Nanosecond(),
 		},
 		Labels: &SLabel{
 			LoggerName: "treactor",
@@ -144,20 +226,28 @@ func (l *SLogger) log(ctx context.Context, severity string, message string) {
 			Version: AppVersion,
 		},
 	}
-	entry = l.addSpan(ctx, entry)
-	b, err := json.Marshal(entry)
-	if err != nil {
-		fmt.Println("error:", err)
+	entry = l.addSpan(record.span, entry)
+	if record.insertId != "" {
+		entry.InsertId = record.insertId
+		entry.Type = reportedErrorEventType
+		entry.Message = record.message + "\n\n" + record.stack
+		entry.Context = &rContext{
+			HttpRequest:    record.request,
+			ReportLocation: record.location,
+		}
 	}
-	os.Stdout.Write(b)
-	os.Stdout.WriteString("\n")
+	return json.Marshal(entry)
 }
 
-func (l *SLogger) addSpan(ctx context.Context, entry *SEntry) *SEntry {
-	span := trace.SpanFromContext(ctx)
-	if span != nil {
-		entry.Trace = fmt.Sprintf("projects/%s/traces/%s", l.projectId, span.SpanContext().TraceID.String())
-		entry.SpanId = span.SpanContext().SpanID.String()
+// addSpan correlates the entry with the trace, Cloud Logging only links the trace when it has the project
+func (l *SLogger) addSpan(spanContext trace.SpanContext, entry *SEntry) *SEntry {
+	if !spanContext.IsValid() {
+		return entry
+	}
+	if l.projectId != "" {
+		entry.Trace = fmt.Sprintf("projects/%s/traces/%s", l.projectId, spanContext.TraceID.String())
 	}
+	entry.SpanId = spanContext.SpanID.String()
+	entry.TraceSampled = spanContext.IsSampled()
 	return entry
 }
@@ -0,0 +1,99 @@
+package resource
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// loggerFunctions are the frames of the logger itself, they are left out of the stack trace
+const loggerFunctions = "github.com/treactor/treactor-go/pkg/resource."
+
+// newInsertId identifies the error entry, it's returned to the client so the entry can be found
+func newInsertId() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// requestContext is the http request in the Error Reporting format, nil without a request
+func requestContext(r *http.Request) *rHttpRequest {
+	if r == nil {
+		return nil
+	}
+	url := r.URL.String()
+	if r.Host != "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		url = fmt.Sprintf("%s://%s%s", scheme, r.Host, r.URL.RequestURI())
+	}
+	remoteIp := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		remoteIp = host
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		remoteIp = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	return &rHttpRequest{
+		Method:    r.Method,
+		Url:       url,
+		UserAgent: r.UserAgent(),
+		Referrer:  r.Referer(),
+		RemoteIp:  remoteIp,
+	}
+}
+
+// stackTrace returns the stack of the caller of the logger, formatted like a Go panic so Error Reporting can
+// parse it, and the location of the caller
+func stackTrace() (string, *rReportLocation) {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var builder strings.Builder
+	builder.WriteString(goroutineHeader())
+	var location *rReportLocation
+	for {
+		frame, more := frames.Next()
+		isLogger := strings.HasPrefix(frame.Function, loggerFunctions) && strings.Contains(frame.Function, "Logger)")
+		if location == nil && (isLogger || frame.File == "<autogenerated>") {
+			if !more {
+				break
+			}
+			continue
+		}
+		if location == nil {
+			location = &rReportLocation{
+				FilePath:     frame.File,
+				LineNumber:   frame.Line,
+				FunctionName: frame.Function,
+			}
+		}
+		fmt.Fprintf(&builder, "\n%s(...)\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	if location == nil {
+		location = &rReportLocation{}
+	}
+	return builder.String(), location
+}
+
+// goroutineHeader is the first line of the stack of the current goroutine, like goroutine 7 [running]:
+func goroutineHeader() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	if i := strings.IndexByte(string(buf), '\n'); i > 0 {
+		return string(buf[:i])
+	}
+	return "goroutine 1 [running]:"
+}
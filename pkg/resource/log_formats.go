@@ -0,0 +1,212 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ecsVersion is the version of the Elastic Common Schema the ECS logger writes
+const ecsVersion = "1.6.0"
+
+// https://www.elastic.co/guide/en/ecs-logging/overview/current/intro.html
+type ecsEntry struct {
+	Timestamp      string `json:"@timestamp"`
+	Level          string `json:"log.level"`
+	Message        string `json:"message"`
+	EcsVersion     string `json:"ecs.version"`
+	Logger         string `json:"log.logger"`
+	ServiceName    string `json:"service.name"`
+	ServiceVersion string `json:"service.version,omitempty"`
+	TraceId        string `json:"trace.id,omitempty"`
+	SpanId         string `json:"span.id,omitempty"`
+
+	// errors only
+	EventId       string `json:"event.id,omitempty"`
+	StackTrace    string `json:"error.stack_trace,omitempty"`
+	OriginFile    string `json:"log.origin.file.name,omitempty"`
+	OriginLine    int    `json:"log.origin.file.line,omitempty"`
+	OriginFunc    string `json:"log.origin.function,omitempty"`
+	RequestMethod string `json:"http.request.method,omitempty"`
+	Url           string `json:"url.original,omitempty"`
+	UserAgent     string `json:"user_agent.original,omitempty"`
+	ClientIp      string `json:"client.ip,omitempty"`
+}
+
+func NewECSLogger(out io.Writer) RLogger {
+	return &baseLogger{
+		out: out,
+		format: func(record *logRecord) ([]byte, error) {
+			entry := &ecsEntry{
+				Timestamp:      record.time.UTC().Format(time.RFC3339Nano),
+				Level:          strings.ToLower(record.severity),
+				Message:        record.message,
+				EcsVersion:     ecsVersion,
+				Logger:         "treactor",
+				ServiceName:    AppName,
+				ServiceVersion: AppVersion,
+			}
+			if record.span.IsValid() {
+				entry.TraceId = record.span.TraceID.String()
+				entry.SpanId = record.span.SpanID.String()
+			}
+			if record.insertId != "" {
+				entry.EventId = record.insertId
+				entry.StackTrace = record.stack
+				entry.OriginFile = record.location.FilePath
+				entry.OriginLine = record.location.LineNumber
+				entry.OriginFunc = record.location.FunctionName
+			}
+			if record.request != nil {
+				entry.RequestMethod = record.request.Method
+				entry.Url = record.request.Url
+				entry.UserAgent = record.request.UserAgent
+				entry.ClientIp = record.request.RemoteIp
+			}
+			return json.Marshal(entry)
+		},
+	}
+}
+
+// https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/logs/data-model.md
+type otelEntry struct {
+	Timestamp      int64                  `json:"Timestamp"`
+	TraceId        string                 `json:"TraceId,omitempty"`
+	SpanId         string                 `json:"SpanId,omitempty"`
+	TraceFlags     string                 `json:"TraceFlags,omitempty"`
+	SeverityText   string                 `json:"SeverityText"`
+	SeverityNumber int                    `json:"SeverityNumber"`
+	Body           string                 `json:"Body"`
+	Resource       map[string]string      `json:"Resource"`
+	Attributes     map[string]interface{} `json:"Attributes,omitempty"`
+}
+
+// otelSeverity maps the severity to the short name and number of the OpenTelemetry log data model
+func otelSeverity(severity string) (string, int) {
+	switch severity {
+	case "DEBUG":
+		return "DEBUG", 5
+	case "WARNING":
+		return "WARN", 13
+	case "ERROR":
+		return "ERROR", 17
+	default:
+		return "INFO", 9
+	}
+}
+
+// otelAttributes are the error details in the semantic conventions of exceptions, source code and http
+func otelAttributes(record *logRecord) map[string]interface{} {
+	if record.insertId == "" {
+		return nil
+	}
+	attributes := map[string]interface{}{
+		"log.record.uid":       record.insertId,
+		"exception.stacktrace": record.stack,
+		"code.filepath":        record.location.FilePath,
+		"code.lineno":          record.location.LineNumber,
+		"code.function":        record.location.FunctionName,
+	}
+	if record.request != nil {
+		attributes["http.method"] = record.request.Method
+		attributes["http.url"] = record.request.Url
+		attributes["http.user_agent"] = record.request.UserAgent
+		attributes["http.client_ip"] = record.request.RemoteIp
+	}
+	return attributes
+}
+
+func NewOTelLogger(out io.Writer) RLogger {
+	return &baseLogger{
+		out: out,
+		format: func(record *logRecord) ([]byte, error) {
+			text, number := otelSeverity(record.severity)
+			entry := &otelEntry{
+				Timestamp:      record.time.UnixNano(),
+				SeverityText:   text,
+				SeverityNumber: number,
+				Body:           record.message,
+				Resource: map[string]string{
+					"service.name":    AppName,
+					"service.version": AppVersion,
+				},
+			}
+			if record.span.IsValid() {
+				entry.TraceId = record.span.TraceID.String()
+				entry.SpanId = record.span.SpanID.String()
+				entry.TraceFlags = fmt.Sprintf("%02x", byte(record.span.TraceFlags))
+			}
+			entry.Attributes = otelAttributes(record)
+			return json.Marshal(entry)
+		},
+	}
+}
+
+// https://brandur.org/logfmt
+func NewLogfmtLogger(out io.Writer) RLogger {
+	return &baseLogger{
+		out: out,
+		format: func(record *logRecord) ([]byte, error) {
+			var b bytes.Buffer
+			writeLogfmt(&b, "time", record.time.UTC().Format(time.RFC3339Nano))
+			writeLogfmt(&b, "level", strings.ToLower(record.severity))
+			writeLogfmt(&b, "msg", record.message)
+			writeLogfmt(&b, "service", AppName)
+			if record.span.IsValid() {
+				writeLogfmt(&b, "trace_id", record.span.TraceID.String())
+				writeLogfmt(&b, "span_id", record.span.SpanID.String())
+			}
+			if record.insertId != "" {
+				writeLogfmt(&b, "error_id", record.insertId)
+				writeLogfmt(&b, "caller", fmt.Sprintf("%s:%d", record.location.FilePath, record.location.LineNumber))
+				if record.request != nil {
+					writeLogfmt(&b, "method", record.request.Method)
+					writeLogfmt(&b, "url", record.request.Url)
+				}
+				writeLogfmt(&b, "stack", record.stack)
+			}
+			return b.Bytes(), nil
+		},
+	}
+}
+
+// writeLogfmt appends key=value, values with spaces, quotes or equal signs are quoted
+func writeLogfmt(b *bytes.Buffer, key string, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	if value == "" || strings.ContainsAny(value, " =\"\t\r\n") {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+// NewTextLogger writes human readable lines, for running treactor in a terminal
+func NewTextLogger(out io.Writer) RLogger {
+	return &baseLogger{
+		out: out,
+		format: func(record *logRecord) ([]byte, error) {
+			// the trace goes before the message, so a multi-line message stays at the end of the entry
+			line := fmt.Sprintf("%s %-7s ", record.time.Format("15:04:05.000"), record.severity)
+			if record.span.IsValid() {
+				line += fmt.Sprintf("[%s %s] ", record.span.TraceID, record.span.SpanID)
+			}
+			line += record.message
+			if record.insertId != "" {
+				line += fmt.Sprintf(" (error %s)", record.insertId)
+				if record.request != nil {
+					line += fmt.Sprintf("\n%s %s", record.request.Method, record.request.Url)
+				}
+				line += "\n" + record.stack
+			}
+			return []byte(line), nil
+		},
+	}
+}
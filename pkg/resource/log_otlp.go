@@ -0,0 +1,193 @@
+package resource
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+)
+
+const (
+	logBufferSize    = 4096
+	logBatchSize     = 512
+	logExportTimeout = 10 * time.Second
+)
+
+// LogExporter sends the log records over OTLP/gRPC next to the output of the logger, nil when
+// OTEL_LOGS_EXPORTER isn't otlp
+var LogExporter *OtlpLogExporter
+
+// OtlpLogExporter buffers the records and exports them in batches from a background goroutine, so logging
+// never waits for the collector. When the buffer is full records are dropped.
+type OtlpLogExporter struct {
+	conn     *grpc.ClientConn
+	client   collectorlogs.LogsServiceClient
+	resource *resourcepb.Resource
+	interval time.Duration
+
+	records chan *logspb.LogRecord
+	flush   chan chan struct{}
+	done    chan struct{}
+	dropped int64
+}
+
+func NewOtlpLogExporter(endpoint string, rs *resource.Resource, interval time.Duration) (*OtlpLogExporter, error) {
+	conn, err := grpc.Dial(endpoint, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	e := &OtlpLogExporter{
+		conn:     conn,
+		client:   collectorlogs.NewLogsServiceClient(conn),
+		resource: &resourcepb.Resource{Attributes: resourceAttributes(rs)},
+		interval: interval,
+		records:  make(chan *logspb.LogRecord, logBufferSize),
+		flush:    make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+// Export queues the record, it doesn't block
+func (e *OtlpLogExporter) Export(record *logRecord) {
+	select {
+	case e.records <- toLogRecord(record):
+	default:
+		atomic.AddInt64(&e.dropped, 1)
+	}
+}
+
+// Flush exports the buffered records and waits till they are sent
+func (e *OtlpLogExporter) Flush() {
+	flushed := make(chan struct{})
+	select {
+	case e.flush <- flushed:
+		<-flushed
+	case <-e.done:
+	}
+}
+
+// Shutdown flushes the buffer and closes the connection
+func (e *OtlpLogExporter) Shutdown() {
+	e.Flush()
+	close(e.done)
+	e.conn.Close()
+}
+
+// Dropped is the number of records that didn't fit in the buffer
+func (e *OtlpLogExporter) Dropped() int64 {
+	return atomic.LoadInt64(&e.dropped)
+}
+
+func (e *OtlpLogExporter) run() {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	batch := make([]*logspb.LogRecord, 0, logBatchSize)
+	for {
+		select {
+		case record := <-e.records:
+			batch = append(batch, record)
+			if len(batch) >= logBatchSize {
+				batch = e.send(batch)
+			}
+		case <-ticker.C:
+			batch = e.send(batch)
+		case flushed := <-e.flush:
+			for drained := false; !drained; {
+				select {
+				case record := <-e.records:
+					batch = append(batch, record)
+				default:
+					drained = true
+				}
+			}
+			batch = e.send(batch)
+			close(flushed)
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// send exports the batch and returns it emptied, a failed export is reported on stderr and not retried
+func (e *OtlpLogExporter) send(batch []*logspb.LogRecord) []*logspb.LogRecord {
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), logExportTimeout)
+	defer cancel()
+	_, err := e.client.Export(ctx, &collectorlogs.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: e.resource,
+			InstrumentationLibraryLogs: []*logspb.InstrumentationLibraryLogs{{
+				InstrumentationLibrary: &commonpb.InstrumentationLibrary{Name: "io.treactor.logging.golang"},
+				Logs:                   batch,
+			}},
+		}},
+	})
+	if err != nil {
+		log.Printf("failed to export %d log records: %v", len(batch), err)
+	}
+	return batch[:0:0]
+}
+
+func toLogRecord(record *logRecord) *logspb.LogRecord {
+	text, number := otelSeverity(record.severity)
+	lr := &logspb.LogRecord{
+		TimeUnixNano:   uint64(record.time.UnixNano()),
+		SeverityNumber: logspb.SeverityNumber(number),
+		SeverityText:   text,
+		Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: record.message}},
+	}
+	if record.span.IsValid() {
+		lr.TraceId = record.span.TraceID[:]
+		lr.SpanId = record.span.SpanID[:]
+		lr.Flags = uint32(record.span.TraceFlags)
+	}
+	attributes := otelAttributes(record)
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := &commonpb.AnyValue{}
+		switch v := attributes[key].(type) {
+		case int:
+			value.Value = &commonpb.AnyValue_IntValue{IntValue: int64(v)}
+		case string:
+			value.Value = &commonpb.AnyValue_StringValue{StringValue: v}
+		}
+		lr.Attributes = append(lr.Attributes, &commonpb.KeyValue{Key: key, Value: value})
+	}
+	return lr
+}
+
+func resourceAttributes(rs *resource.Resource) []*commonpb.KeyValue {
+	var attributes []*commonpb.KeyValue
+	for _, kv := range rs.Attributes() {
+		value := &commonpb.AnyValue{}
+		switch kv.Value.Type() {
+		case attribute.BOOL:
+			value.Value = &commonpb.AnyValue_BoolValue{BoolValue: kv.Value.AsBool()}
+		case attribute.INT64:
+			value.Value = &commonpb.AnyValue_IntValue{IntValue: kv.Value.AsInt64()}
+		case attribute.FLOAT64:
+			value.Value = &commonpb.AnyValue_DoubleValue{DoubleValue: kv.Value.AsFloat64()}
+		default:
+			value.Value = &commonpb.AnyValue_StringValue{StringValue: kv.Value.Emit()}
+		}
+		attributes = append(attributes, &commonpb.KeyValue{Key: string(kv.Key), Value: value})
+	}
+	return attributes
+}
@@ -0,0 +1,76 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/semconv"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+)
+
+type logsServer struct {
+	collectorlogs.UnimplementedLogsServiceServer
+	mu       sync.Mutex
+	requests []*collectorlogs.ExportLogsServiceRequest
+}
+
+func (s *logsServer) Export(_ context.Context, request *collectorlogs.ExportLogsServiceRequest) (*collectorlogs.ExportLogsServiceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, request)
+	return &collectorlogs.ExportLogsServiceResponse{}, nil
+}
+
+func TestOtlpLogExporter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	received := &logsServer{}
+	server := grpc.NewServer()
+	collectorlogs.RegisterLogsServiceServer(server, received)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	rs := resource.NewWithAttributes(semconv.ServiceNameKey.String("atom-h"))
+	LogExporter, err = NewOtlpLogExporter(listener.Addr().String(), rs, time.Hour)
+	assert.NoError(t, err)
+	defer func() { LogExporter = nil }()
+
+	var out bytes.Buffer
+	logger := NewTextLogger(&out)
+	ctx, sc := spanContext()
+	logger.Info(ctx, "hello")
+	insertId := logger.Error(context.Background(), nil, "failed")
+	logger.Flush()
+	LogExporter.Shutdown()
+
+	assert.Contains(t, out.String(), "hello")
+	assert.Len(t, received.requests, 1)
+	resourceLogs := received.requests[0].ResourceLogs[0]
+	assert.Equal(t, "service.name", resourceLogs.Resource.Attributes[0].Key)
+	assert.Equal(t, "atom-h", resourceLogs.Resource.Attributes[0].Value.GetStringValue())
+
+	records := resourceLogs.InstrumentationLibraryLogs[0].Logs
+	assert.Len(t, records, 2)
+	assert.Equal(t, "hello", records[0].Body.GetStringValue())
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_INFO, records[0].SeverityNumber)
+	assert.Equal(t, sc.TraceID[:], records[0].TraceId)
+	assert.Equal(t, sc.SpanID[:], records[0].SpanId)
+	assert.Equal(t, uint32(1), records[0].Flags)
+
+	assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, records[1].SeverityNumber)
+	assert.Nil(t, records[1].TraceId)
+	attributes := make(map[string]string)
+	for _, kv := range records[1].Attributes {
+		attributes[kv.Key] = kv.Value.GetStringValue()
+	}
+	assert.Equal(t, insertId, attributes["log.record.uid"])
+	assert.Contains(t, attributes["exception.stacktrace"], "TestOtlpLogExporter")
+}
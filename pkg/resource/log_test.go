@@ -0,0 +1,118 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanContext() (context.Context, trace.SpanContext) {
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+	return ctx, span.SpanContext()
+}
+
+func logLine(method string, ctx context.Context, message string) string {
+	var out bytes.Buffer
+	NewLogger(method, &out).Warning(ctx, message)
+	return out.String()
+}
+
+func TestECSLogger(t *testing.T) {
+	ctx, sc := spanContext()
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(logLine("ecs", ctx, "hello")), &entry))
+	assert.Equal(t, "warning", entry["log.level"])
+	assert.Equal(t, "hello", entry["message"])
+	assert.Equal(t, sc.TraceID.String(), entry["trace.id"])
+	assert.Equal(t, sc.SpanID.String(), entry["span.id"])
+}
+
+func TestOTelLogger(t *testing.T) {
+	ctx, sc := spanContext()
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(logLine("otel", ctx, "hello")), &entry))
+	assert.Equal(t, "WARN", entry["SeverityText"])
+	assert.Equal(t, float64(13), entry["SeverityNumber"])
+	assert.Equal(t, "hello", entry["Body"])
+	assert.Equal(t, sc.TraceID.String(), entry["TraceId"])
+	assert.Equal(t, sc.SpanID.String(), entry["SpanId"])
+	assert.Equal(t, "01", entry["TraceFlags"])
+}
+
+func TestLogfmtLogger(t *testing.T) {
+	ctx, sc := spanContext()
+	line := logLine("logfmt", ctx, "hello world")
+	assert.Contains(t, line, ` level=warning msg="hello world" `)
+	assert.Contains(t, line, " trace_id="+sc.TraceID.String()+" span_id="+sc.SpanID.String()+"\n")
+
+	line = logLine("logfmt", context.Background(), "no span")
+	assert.NotContains(t, line, "trace_id")
+}
+
+func TestTextLogger(t *testing.T) {
+	ctx, sc := spanContext()
+	line := logLine("text", ctx, "hello")
+	assert.Contains(t, line, " WARNING ["+sc.TraceID.String()+" "+sc.SpanID.String()+"] hello\n")
+}
+
+func TestGcpLogger(t *testing.T) {
+	ctx, sc := spanContext()
+	var entry SEntry
+	var out bytes.Buffer
+	l := NewSLogger("chemistry")
+	l.out = &out
+	l.Info(ctx, "hello")
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "projects/chemistry/traces/"+sc.TraceID.String(), entry.Trace)
+	assert.Equal(t, sc.SpanID.String(), entry.SpanId)
+	assert.True(t, entry.TraceSampled)
+
+	out.Reset()
+	l.Info(context.Background(), "no span")
+	assert.NotContains(t, out.String(), "logging.googleapis.com/trace")
+	assert.NotContains(t, out.String(), "logging.googleapis.com/spanId")
+}
+
+func TestGcpErrorEntry(t *testing.T) {
+	var out bytes.Buffer
+	l := NewSLogger("chemistry")
+	l.out = &out
+	r := httptest.NewRequest("GET", "http://treactor/treact/reactions?molecule=H2", nil)
+	r.Header.Set("User-Agent", "test")
+	insertId := l.ErrorErr(context.Background(), r, "Unable to parse molecule", errors.New("Only s or p accepted"))
+
+	var entry SEntry
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.NotEmpty(t, insertId)
+	assert.Equal(t, insertId, entry.InsertId)
+	assert.Equal(t, reportedErrorEventType, entry.Type)
+	assert.True(t, strings.HasPrefix(entry.Message, "Unable to parse molecule: Only s or p accepted\n\ngoroutine "))
+	assert.Equal(t, &rHttpRequest{
+		Method:    "GET",
+		Url:       "http://treactor/treact/reactions?molecule=H2",
+		UserAgent: "test",
+		RemoteIp:  "192.0.2.1",
+	}, entry.Context.HttpRequest)
+	assert.Equal(t, "github.com/treactor/treactor-go/pkg/resource.TestGcpErrorEntry", entry.Context.ReportLocation.FunctionName)
+	assert.True(t, strings.HasSuffix(entry.Context.ReportLocation.FilePath, "log_test.go"))
+}
+
+func TestECSErrorEntry(t *testing.T) {
+	var out bytes.Buffer
+	insertId := NewLogger("ecs", &out).Error(context.Background(), nil, "failed")
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, insertId, entry["event.id"])
+	assert.Equal(t, "github.com/treactor/treactor-go/pkg/resource.TestECSErrorEntry", entry["log.origin.function"])
+	assert.Contains(t, entry["error.stack_trace"], "resource.TestECSErrorEntry(...)")
+	assert.NotContains(t, entry, "http.request.method")
+}
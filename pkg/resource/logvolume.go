@@ -0,0 +1,168 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogVolume are the log lines asked for by the log KV annotations:
+//
+//	log:N[@level]          N lines at level info (default), warning or error
+//	logsize:N              pad the message to N bytes (k and m suffixes allowed)
+//	logformat:plain        plain message (default)
+//	logformat:json         the message is a JSON document
+//	logformat:multiline    the message is followed by a stack trace, one line per frame
+type LogVolume struct {
+	Count    int
+	Severity string
+	Size     int
+	Format   string
+}
+
+const maxLogCount = 100000
+
+func ParseLogVolume(kv map[string]string) (*LogVolume, error) {
+	volume := &LogVolume{
+		Severity: "INFO",
+		Format:   "plain",
+	}
+	spec := strings.SplitN(kv["log"], "@", 2)
+	count, err := strconv.Atoi(spec[0])
+	if err != nil || count < 0 || count > maxLogCount {
+		return nil, fmt.Errorf("log needs a number of lines between 0 and %d", maxLogCount)
+	}
+	volume.Count = count
+	if len(spec) == 2 {
+		switch strings.ToLower(spec[1]) {
+		case "info":
+			volume.Severity = "INFO"
+		case "warn", "warning":
+			volume.Severity = "WARNING"
+		case "error":
+			volume.Severity = "ERROR"
+		default:
+			return nil, fmt.Errorf("unknown log level %s", spec[1])
+		}
+	}
+	if size, ok := kv["logsize"]; ok {
+		volume.Size, err = ParseSize(size)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if format, ok := kv["logformat"]; ok {
+		if format != "plain" && format != "json" && format != "multiline" {
+			return nil, fmt.Errorf("unknown log format %s", format)
+		}
+		volume.Format = format
+	}
+	return volume, nil
+}
+
+// ParseSize parses a number of bytes with an optional k, m or g suffix (powers of 1024)
+func ParseSize(value string) (int, error) {
+	size, err := ParseQuantity(value, 1024)
+	if err != nil {
+		return 0, errors.New("size needs a positive number")
+	}
+	return size, nil
+}
+
+// ParseQuantity parses a positive number with an optional k, m or g suffix, powers of the base (1000 or 1024)
+func ParseQuantity(value string, base int) (int, error) {
+	if value == "" {
+		return 0, errors.New("quantity needs a positive number")
+	}
+	multiplier := 1
+	switch strings.ToLower(value[len(value)-1:]) {
+	case "k":
+		multiplier = base
+	case "m":
+		multiplier = base * base
+	case "g":
+		multiplier = base * base * base
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+	quantity, err := strconv.ParseFloat(value, 64)
+	if err != nil || quantity < 0 {
+		return 0, errors.New("quantity needs a positive number")
+	}
+	return int(quantity * float64(multiplier)), nil
+}
+
+// EmitLogs emits the lines of the log KV annotations of an atom or a block and records a log event on the span,
+// malformed annotations are ignored with a warning
+func EmitLogs(ctx context.Context, kv map[string]string) {
+	volume, err := ParseLogVolume(kv)
+	if err != nil {
+		Logger.WarningF(ctx, "Ignoring log action: %s", err)
+		return
+	}
+	volume.Emit(ctx)
+	trace.SpanFromContext(ctx).AddEvent("log", trace.WithAttributes(
+		ActionKey.String("log"),
+		ActionValueKey.String(kv["log"]),
+		attribute.Int("treactor.log.count", volume.Count)))
+}
+
+// Emit writes the lines with the logger, so they carry the trace and span of the context
+func (v *LogVolume) Emit(ctx context.Context) {
+	for i := 1; i <= v.Count; i++ {
+		message := v.Message(i)
+		switch v.Severity {
+		case "WARNING":
+			Logger.Warning(ctx, message)
+		case "ERROR":
+			Logger.Error(ctx, nil, message)
+		default:
+			Logger.Info(ctx, message)
+		}
+	}
+}
+
+// Message is the i-th generated message
+func (v *LogVolume) Message(i int) string {
+	message := fmt.Sprintf("treactor log line %d of %d", i, v.Count)
+	switch v.Format {
+	case "json":
+		document := map[string]interface{}{
+			"message": message,
+			"line":    i,
+			"count":   v.Count,
+		}
+		if b, _ := json.Marshal(document); len(b) < v.Size {
+			document["padding"] = padding(v.Size - len(b) - len(`,"padding":""`))
+		}
+		b, _ := json.Marshal(document)
+		return string(b)
+	case "multiline":
+		var builder strings.Builder
+		builder.WriteString(message)
+		builder.WriteString("\ngoroutine 1 [running]:")
+		for frame := 0; frame == 0 || builder.Len() < v.Size; frame++ {
+			fmt.Fprintf(&builder, "\ngithub.com/treactor/treactor-go/pkg/treact.frame%d(...)\n\t/app/pkg/treact/actions.go:%d +0x%x", frame, 100+frame, 16*frame)
+		}
+		return builder.String()
+	default:
+		if len(message) < v.Size {
+			message += " " + padding(v.Size-len(message)-1)
+		}
+		return message
+	}
+}
+
+func padding(size int) string {
+	if size <= 0 {
+		return ""
+	}
+	return strings.Repeat("x", size)
+}
@@ -0,0 +1,94 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLogVolume(t *testing.T) {
+	volume, err := ParseLogVolume(map[string]string{"log": "5@warning", "logsize": "2k", "logformat": "json"})
+	assert.NoError(t, err)
+	assert.Equal(t, &LogVolume{Count: 5, Severity: "WARNING", Size: 2048, Format: "json"}, volume)
+
+	volume, err = ParseLogVolume(map[string]string{"log": "3"})
+	assert.NoError(t, err)
+	assert.Equal(t, &LogVolume{Count: 3, Severity: "INFO", Format: "plain"}, volume)
+
+	for _, kv := range []map[string]string{
+		{"log": "x"},
+		{"log": "5@trace"},
+		{"log": "5", "logsize": "big"},
+		{"log": "5", "logformat": "xml"},
+	} {
+		_, err = ParseLogVolume(kv)
+		assert.Error(t, err, kv)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for value, expected := range map[string]int{"0": 0, "512": 512, "2k": 2048, "1.5m": 1536 * 1024, "1G": 1024 * 1024 * 1024} {
+		size, err := ParseSize(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, size, value)
+	}
+	for _, value := range []string{"", "k", "-1", "big"} {
+		_, err := ParseSize(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	count, err := ParseQuantity("2.5k", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 2500, count)
+	count, err = ParseQuantity("1m", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000*1000, count)
+	_, err = ParseQuantity("", 1000)
+	assert.Error(t, err)
+}
+
+func TestLogVolumeMessage(t *testing.T) {
+	plain := &LogVolume{Count: 2, Format: "plain", Size: 100}
+	assert.Len(t, plain.Message(1), 100)
+	assert.True(t, strings.HasPrefix(plain.Message(1), "treactor log line 1 of 2 "))
+
+	var document map[string]interface{}
+	js := &LogVolume{Count: 2, Format: "json", Size: 200}
+	assert.NoError(t, json.Unmarshal([]byte(js.Message(2)), &document))
+	assert.Equal(t, float64(2), document["line"])
+	assert.Len(t, js.Message(2), 200)
+
+	multiline := &LogVolume{Count: 1, Format: "multiline", Size: 500}
+	lines := strings.Split(multiline.Message(1), "\n")
+	assert.Equal(t, "goroutine 1 [running]:", lines[1])
+	assert.True(t, len(multiline.Message(1)) >= 500)
+}
+
+func TestLogVolumeEmit(t *testing.T) {
+	var out bytes.Buffer
+	defer func(logger RLogger) { Logger = logger }(Logger)
+	Logger = NewTextLogger(&out)
+
+	(&LogVolume{Count: 3, Severity: "ERROR", Format: "plain"}).Emit(context.Background())
+	assert.Equal(t, 3, strings.Count(out.String(), " ERROR   treactor log line "))
+}
+
+func TestEmitLogs(t *testing.T) {
+	var out bytes.Buffer
+	defer func(logger RLogger) { Logger = logger }(Logger)
+	Logger = NewTextLogger(&out)
+
+	EmitLogs(context.Background(), map[string]string{"log": "2@warning"})
+	assert.Equal(t, 2, strings.Count(out.String(), " WARNING treactor log line "))
+
+	out.Reset()
+	EmitLogs(context.Background(), map[string]string{"log": "2@trace"})
+	assert.Contains(t, out.String(), "Ignoring log action")
+	assert.NotContains(t, out.String(), "treactor log line")
+}
@@ -1,6 +1,10 @@
 package resource
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/treactor/treactor-go/pkg/element"
 )
 
@@ -10,9 +14,21 @@ var (
 
 var Atoms *element.Atoms
 
-func Init() {
+func Init() error {
 	initTelemetry()
 	clientInit()
-	Logger = NewSLogger("")
-	Atoms = element.NewAtoms()
+	if LogMethod == "gcp" && GcpProject == "" && onGcp() {
+		GcpProject = detectGcpProject(context.Background())
+	}
+	Logger = NewLogger(LogMethod, os.Stdout)
+
+	var err error
+	Atoms, err = element.Load(ElementsFile)
+	if err != nil {
+		return fmt.Errorf("failed to load elements: %w", err)
+	}
+	for _, problem := range Atoms.Problems {
+		Logger.WarningF(context.Background(), "Malformed element: %v", problem)
+	}
+	return nil
 }
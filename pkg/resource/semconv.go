@@ -0,0 +1,59 @@
+package resource
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/treactor/treactor-go/pkg/element"
+)
+
+// Span attributes, all prefixed with treactor. so traces can be queried by chemistry
+const (
+	MoleculeKey   = attribute.Key("treactor.molecule")
+	PlanKey       = attribute.Key("treactor.plan")
+	BlockIndexKey = attribute.Key("treactor.block.index")
+	RepetitionKey = attribute.Key("treactor.block.repetition")
+	ModeKey       = attribute.Key("treactor.block.mode")
+	TimesKey      = attribute.Key("treactor.block.times")
+	BondDepthKey  = attribute.Key("treactor.bond.depth")
+	SelectorKey   = attribute.Key("treactor.selector")
+	SeedKey       = attribute.Key("treactor.seed")
+	HopPathKey    = attribute.Key("treactor.hop.path")
+
+	ChoiceKey       = attribute.Key("treactor.choice")
+	ChoiceBranchKey = attribute.Key("treactor.choice.branch")
+
+	AtomSymbolKey   = attribute.Key("treactor.atom.symbol")
+	AtomNameKey     = attribute.Key("treactor.atom.name")
+	AtomNumberKey   = attribute.Key("treactor.atom.number")
+	AtomPeriodKey   = attribute.Key("treactor.atom.period")
+	AtomGroupKey    = attribute.Key("treactor.atom.group")
+	AtomCategoryKey = attribute.Key("treactor.atom.category")
+
+	ActionKey      = attribute.Key("treactor.action")
+	ActionValueKey = attribute.Key("treactor.action.value")
+
+	FaultKey      = attribute.Key("treactor.fault")
+	FaultErrorKey = attribute.Key("treactor.fault.error")
+
+	// KVPrefix is followed by the key of the KV annotation, eg. treactor.kv.cpu
+	KVPrefix = "treactor.kv."
+)
+
+func KVAttributes(kv map[string]string) []attribute.KeyValue {
+	attributes := make([]attribute.KeyValue, 0, len(kv))
+	for k, v := range kv {
+		attributes = append(attributes, attribute.String(KVPrefix+k, v))
+	}
+	return attributes
+}
+
+func AtomAttributes(atom element.Atom) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AtomSymbolKey.String(atom.Symbol),
+		AtomNameKey.String(atom.Name),
+		AtomNumberKey.Int64(int64(atom.Number)),
+		AtomPeriodKey.Int64(int64(atom.Period)),
+		AtomGroupKey.Int64(int64(atom.Group)),
+		AtomCategoryKey.String(atom.Category.String()),
+	}
+}
@@ -0,0 +1,24 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestKVAttributes(t *testing.T) {
+	for _, test := range []struct {
+		kv       map[string]string
+		expected []attribute.KeyValue
+	}{
+		{map[string]string{}, []attribute.KeyValue{}},
+		{map[string]string{"sleep": "100"}, []attribute.KeyValue{attribute.String("treactor.kv.sleep", "100")}},
+		{map[string]string{"cpu": "500ms@2", "fail": "10"}, []attribute.KeyValue{
+			attribute.String("treactor.kv.cpu", "500ms@2"),
+			attribute.String("treactor.kv.fail", "10"),
+		}},
+	} {
+		assert.ElementsMatch(t, test.expected, KVAttributes(test.kv), test.kv)
+	}
+}
@@ -0,0 +1,159 @@
+package resource
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Settings are the behaviors of the instance that can change while it runs, with the admin API. They start from
+// the environment variables.
+type Settings struct {
+	// Latency is added to every atom and bond
+	Latency time.Duration
+	// ErrorRate is the percentage of the atoms and bonds that fail with a 500
+	ErrorRate float64
+	// Ready is false when the instance reports it isn't ready for traffic
+	Ready bool
+	// LogLevel is the lowest severity logged: info, warning or error
+	LogLevel string
+	// Sampler is always_on, always_off, traceidratio:R or one of them based on the parent, like
+	// parentbased_traceidratio:0.1
+	Sampler string
+}
+
+// SettingsUpdate has the settings to change, the missing ones keep their value, like {"latency":"200ms"}
+type SettingsUpdate struct {
+	Latency   *string  `json:"latency,omitempty"`
+	ErrorRate *float64 `json:"errorRate,omitempty"`
+	Ready     *bool    `json:"ready,omitempty"`
+	LogLevel  *string  `json:"logLevel,omitempty"`
+	Sampler   *string  `json:"sampler,omitempty"`
+}
+
+var (
+	settingsMu sync.RWMutex
+	settings   = Settings{Ready: true, LogLevel: "info", Sampler: "always_on"}
+)
+
+// logLevels ranks the severities, a record is logged when its severity is at least the log level
+var logLevels = map[string]int{"INFO": 0, "WARNING": 1, "ERROR": 2}
+
+func configureSettings() {
+	sampler := getEnv("OTEL_TRACES_SAMPLER", "always_on")
+	if arg := getEnv("OTEL_TRACES_SAMPLER_ARG", ""); arg != "" {
+		sampler += ":" + arg
+	}
+	update := SettingsUpdate{
+		Latency:  stringSetting("TREACTOR_LATENCY"),
+		LogLevel: stringSetting("TREACTOR_LOG_LEVEL"),
+		Sampler:  &sampler,
+	}
+	if value := getEnv("TREACTOR_ERROR_RATE", ""); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Printf("ignoring TREACTOR_ERROR_RATE: %s", value)
+		} else {
+			update.ErrorRate = &rate
+		}
+	}
+	if _, err := UpdateSettings(update); err != nil {
+		log.Printf("ignoring settings: %v", err)
+	}
+}
+
+func stringSetting(key string) *string {
+	if value := getEnv(key, ""); value != "" {
+		return &value
+	}
+	return nil
+}
+
+// CurrentSettings returns a copy of the settings
+func CurrentSettings() Settings {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return settings
+}
+
+// UpdateSettings changes the settings of the update, nothing changes when one of them is invalid
+func UpdateSettings(update SettingsUpdate) (Settings, error) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	changed := settings
+	if update.Latency != nil {
+		latency, err := ParseDuration(*update.Latency)
+		if err != nil || latency < 0 {
+			return settings, fmt.Errorf("latency needs a duration, like 200ms")
+		}
+		changed.Latency = latency
+	}
+	if update.ErrorRate != nil {
+		if *update.ErrorRate < 0 || *update.ErrorRate > 100 {
+			return settings, fmt.Errorf("errorRate needs a percentage between 0 and 100")
+		}
+		changed.ErrorRate = *update.ErrorRate
+	}
+	if update.Ready != nil {
+		changed.Ready = *update.Ready
+	}
+	if update.LogLevel != nil {
+		if _, ok := logLevels[strings.ToUpper(*update.LogLevel)]; !ok {
+			return settings, fmt.Errorf("logLevel needs info, warning or error")
+		}
+		changed.LogLevel = strings.ToLower(*update.LogLevel)
+	}
+	var sampler sdktrace.Sampler
+	if update.Sampler != nil {
+		var err error
+		if sampler, err = ParseSampler(*update.Sampler); err != nil {
+			return settings, err
+		}
+		changed.Sampler = *update.Sampler
+	}
+	settings = changed
+	if sampler != nil && tracerProvider != nil {
+		tracerProvider.ApplyConfig(sdktrace.Config{DefaultSampler: sampler, Resource: tracerResource})
+	}
+	return settings, nil
+}
+
+// ParseSampler parses the sampler setting, the names are the ones of OTEL_TRACES_SAMPLER
+func ParseSampler(value string) (sdktrace.Sampler, error) {
+	spec := strings.SplitN(value, ":", 2)
+	name := spec[0]
+	parentBased := strings.HasPrefix(name, "parentbased_")
+	var sampler sdktrace.Sampler
+	switch strings.TrimPrefix(name, "parentbased_") {
+	case "always_on":
+		sampler = sdktrace.AlwaysSample()
+	case "always_off":
+		sampler = sdktrace.NeverSample()
+	case "traceidratio":
+		ratio := 1.0
+		if len(spec) == 2 {
+			var err error
+			ratio, err = strconv.ParseFloat(spec[1], 64)
+			if err != nil || ratio < 0 || ratio > 1 {
+				return nil, fmt.Errorf("sampler %s needs a ratio between 0 and 1", name)
+			}
+		}
+		sampler = sdktrace.TraceIDRatioBased(ratio)
+	default:
+		return nil, fmt.Errorf("unknown sampler %s", name)
+	}
+	if parentBased {
+		sampler = sdktrace.ParentBased(sampler)
+	}
+	return sampler, nil
+}
+
+// logged tells if a record of the severity is written at the log level of the settings
+func logged(severity string) bool {
+	return logLevels[severity] >= logLevels[strings.ToUpper(CurrentSettings().LogLevel)]
+}
@@ -0,0 +1,39 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateSettings(t *testing.T) {
+	defer func(saved Settings) { settings = saved }(CurrentSettings())
+	latency, rate, ready, level := "250", 20.0, false, "WARNING"
+	updated, err := UpdateSettings(SettingsUpdate{Latency: &latency, ErrorRate: &rate, Ready: &ready, LogLevel: &level})
+	assert.NoError(t, err)
+	assert.Equal(t, "250ms", updated.Latency.String())
+	assert.Equal(t, 20.0, updated.ErrorRate)
+	assert.False(t, updated.Ready)
+	assert.Equal(t, "warning", updated.LogLevel)
+	assert.False(t, logged("INFO"))
+	assert.True(t, logged("WARNING"))
+
+	// an invalid setting changes nothing
+	latency, rate = "1s", 120
+	_, err = UpdateSettings(SettingsUpdate{Latency: &latency, ErrorRate: &rate})
+	assert.Error(t, err)
+	assert.Equal(t, updated, CurrentSettings())
+}
+
+func TestParseSampler(t *testing.T) {
+	for _, value := range []string{"always_on", "always_off", "traceidratio:0.25", "parentbased_always_on", "parentbased_traceidratio:0.1"} {
+		_, err := ParseSampler(value)
+		assert.NoError(t, err, value)
+	}
+	sampler, _ := ParseSampler("parentbased_traceidratio:0.1")
+	assert.Contains(t, sampler.Description(), "ParentBased")
+	for _, value := range []string{"sometimes", "traceidratio:2", "traceidratio:x"} {
+		_, err := ParseSampler(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -6,21 +6,95 @@ import (
 	"go.opentelemetry.io/otel/exporters/otlp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
 	"go.opentelemetry.io/otel/propagation"
 	exportmetric "go.opentelemetry.io/otel/sdk/export/metric"
 	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	"log"
+	"time"
+
+	"github.com/treactor/treactor-go/pkg/tracestore"
 )
 
 var Int64ValueRecorder metric.Int64ValueRecorder
 var Tracer trace.Tracer
 
+var tracerProvider *sdktrace.TracerProvider
+
+// tracerResource is kept to change the sampler of the tracer provider, it takes the resource along
+var tracerResource *resource.Resource
+
+var metricController *controller.Controller
+
+// TraceStore keeps the recent traces in memory, nil when TREACTOR_TRACE_STORE=0
+var TraceStore *tracestore.Store
+
+// Granularity controls which internal spans are created, the http server and client spans are always there
+type Granularity int
+
+const (
+	// GranularityNone only has the http server and client spans
+	GranularityNone Granularity = iota
+	// GranularityHop adds a span per handler
+	GranularityHop
+	// GranularityPlan adds a span per executed block and operator
+	GranularityPlan
+	// GranularityVerbose adds a span per call and the http client trace (dns, connect, ...)
+	GranularityVerbose
+)
+
+var SpanGranularity = GranularityVerbose
+
+// ParseGranularity parses TREACTOR_TRACE_GRANULARITY, an unknown value is verbose
+func ParseGranularity(value string) Granularity {
+	switch value {
+	case "none":
+		return GranularityNone
+	case "hop":
+		return GranularityHop
+	case "plan":
+		return GranularityPlan
+	case "verbose":
+		return GranularityVerbose
+	default:
+		log.Printf("unknown trace granularity %s, using verbose", value)
+		return GranularityVerbose
+	}
+}
+
+var noopTracer = trace.NewNoopTracerProvider().Tracer("")
+
+// StartSpan starts a span if the configured granularity includes the level. If not the context is
+// returned unchanged together with a span that records nothing.
+func StartSpan(ctx context.Context, level Granularity, name string, opts ...trace.SpanOption) (context.Context, trace.Span) {
+	if level > SpanGranularity {
+		_, span := noopTracer.Start(ctx, name)
+		return ctx, span
+	}
+	return Tracer.Start(ctx, name, opts...)
+}
+
 func initTelemetry() {
 	ctx := context.Background()
 
+	rs, err := detectResource(ctx)
+	if err != nil {
+		// A partial resource is still useful, only report what went wrong
+		log.Printf("failed to detect resource: %v", err)
+	}
+
+	// With OTEL_TRACES_EXPORTER=none nothing is exported, the in-memory trace store can still be used
+	if TracesExporter == "none" {
+		initTracer(nil, rs)
+		return
+	}
+
 	driver := otlpgrpc.NewDriver(
 		otlpgrpc.WithInsecure(),
 		otlpgrpc.WithEndpoint(OtlpEndpoint),
@@ -32,30 +106,77 @@ func initTelemetry() {
 		log.Fatalf("failed to create exporter: %v", err)
 	}
 
-	rs, err := resource.Detect(context.Background(), &resource.FromEnv{})
-	if err != nil {
-		log.Fatalf("failed to create resource: %v", err)
-	}
-
 	initTracer(otlpExporter, rs)
 	initMetrics(otlpExporter, rs)
+
+	if LogsExporter == "otlp" {
+		LogExporter, err = NewOtlpLogExporter(OtlpEndpoint, rs, time.Duration(LogExportDelayMs)*time.Millisecond)
+		if err != nil {
+			log.Fatalf("failed to create log exporter: %v", err)
+		}
+	}
+}
+
+// Shutdown sends the telemetry that is still buffered
+func Shutdown(ctx context.Context) {
+	if Logger != nil {
+		Logger.Flush()
+	}
+	if LogExporter != nil {
+		LogExporter.Shutdown()
+	}
+	if metricController != nil {
+		if err := metricController.Stop(ctx); err != nil {
+			log.Printf("failed to stop metric controller: %v", err)
+		}
+	}
+	if tracerProvider != nil {
+		if err := tracerProvider.Shutdown(ctx); err != nil {
+			log.Printf("failed to shutdown tracer provider: %v", err)
+		}
+	}
 }
 
 func initTracer(exporter exporttrace.SpanExporter, rs *resource.Resource) {
-	// For the demonstration, use sdktrace.AlwaysSample sampler to sample all traces.
-	// In a production application, use sdktrace.ProbabilitySampler with a desired probability.
-	tp := sdktrace.NewTracerProvider(sdktrace.WithConfig(
-		sdktrace.Config{
-			DefaultSampler: sdktrace.AlwaysSample(),
-			Resource:       rs,
-		}),
-		sdktrace.WithSyncer(exporter))
-
-	otel.SetTracerProvider(tp)
+	// The sampler of the settings samples all traces by default, it can change at runtime with the admin API
+	sampler, err := ParseSampler(CurrentSettings().Sampler)
+	if err != nil {
+		sampler = sdktrace.AlwaysSample()
+	}
+	tracerResource = rs
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithConfig(
+			sdktrace.Config{
+				DefaultSampler: sampler,
+				Resource:       rs,
+			}),
+	}
+	if exporter != nil {
+		options = append(options, sdktrace.WithSyncer(exporter))
+	}
+	if TraceStoreSize > 0 {
+		TraceStore = tracestore.NewStore(TraceStoreSize)
+		options = append(options, sdktrace.WithSyncer(TraceStore))
+	}
+	tracerProvider = sdktrace.NewTracerProvider(options...)
+
+	otel.SetTracerProvider(tracerProvider)
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 	Tracer = otel.GetTracerProvider().Tracer("io.treactor.tracing.golang", trace.WithInstrumentationVersion("0.5"))
 }
 
-func initMetrics(_ exportmetric.Exporter, _ *resource.Resource) {
-	// TODO
+// metricInterval is how often the metrics are collected and pushed to the exporter
+const metricInterval = 10 * time.Second
+
+func initMetrics(exporter exportmetric.Exporter, rs *resource.Resource) {
+	metricController = controller.New(
+		processor.New(simple.NewWithInexpensiveDistribution(), exporter),
+		controller.WithPusher(exporter),
+		controller.WithResource(rs),
+		controller.WithCollectPeriod(metricInterval),
+	)
+	if err := metricController.Start(context.Background()); err != nil {
+		log.Fatalf("failed to start metric controller: %v", err)
+	}
+	global.SetMeterProvider(metricController.MeterProvider())
 }
@@ -0,0 +1,43 @@
+package resource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseGranularity(t *testing.T) {
+	for value, expected := range map[string]Granularity{
+		"none":    GranularityNone,
+		"hop":     GranularityHop,
+		"plan":    GranularityPlan,
+		"verbose": GranularityVerbose,
+		"verbos":  GranularityVerbose,
+		"":        GranularityVerbose,
+	} {
+		assert.Equal(t, expected, ParseGranularity(value), value)
+	}
+}
+
+func TestStartSpan(t *testing.T) {
+	defer func(tracer trace.Tracer, granularity Granularity) {
+		Tracer, SpanGranularity = tracer, granularity
+	}(Tracer, SpanGranularity)
+	Tracer = sdktrace.NewTracerProvider().Tracer("test")
+	SpanGranularity = GranularityHop
+
+	ctx := context.Background()
+	hopCtx, hop := StartSpan(ctx, GranularityHop, "hop")
+	assert.True(t, hop.IsRecording())
+	assert.Equal(t, hop, trace.SpanFromContext(hopCtx))
+
+	// a finer level than the granularity leaves the context as it was
+	planCtx, plan := StartSpan(hopCtx, GranularityPlan, "plan")
+	assert.False(t, plan.IsRecording())
+	assert.Equal(t, hopCtx, planCtx)
+	plan.End()
+	hop.End()
+}
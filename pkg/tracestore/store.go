@@ -0,0 +1,187 @@
+package tracestore
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+// MaxSpansPerTrace protects the store against a single runaway trace
+const MaxSpansPerTrace = 10000
+
+type Event struct {
+	Name       string            `json:"name"`
+	Time       time.Time         `json:"time"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type Span struct {
+	TraceID       string            `json:"traceId"`
+	SpanID        string            `json:"spanId"`
+	ParentSpanID  string            `json:"parentSpanId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind"`
+	Service       string            `json:"service,omitempty"`
+	StartTime     time.Time         `json:"startTime"`
+	EndTime       time.Time         `json:"endTime"`
+	Status        string            `json:"status,omitempty"`
+	StatusMessage string            `json:"statusMessage,omitempty"`
+	Attributes    map[string]string `json:"attributes,omitempty"`
+	Events        []Event           `json:"events,omitempty"`
+}
+
+func (s *Span) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+type storedTrace struct {
+	id    string
+	spans []*Span
+}
+
+// Store is an in-process span exporter that keeps the spans of the most recent traces in a
+// bounded ring buffer, so a reaction can be inspected without a collector or backend.
+type Store struct {
+	mu     sync.RWMutex
+	size   int
+	traces map[string]*storedTrace
+	ring   []string
+	next   int
+}
+
+var _ exporttrace.SpanExporter = (*Store)(nil)
+
+func NewStore(size int) *Store {
+	return &Store{
+		size:   size,
+		traces: make(map[string]*storedTrace, size),
+		ring:   make([]string, size),
+	}
+}
+
+func (s *Store) ExportSpans(_ context.Context, snapshots []*exporttrace.SpanSnapshot) error {
+	spans := make([]*Span, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		spans = append(spans, fromSnapshot(snapshot))
+	}
+	s.Add(spans...)
+	return nil
+}
+
+func (s *Store) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// Add stores the spans, evicting the oldest trace when a new trace doesn't fit anymore
+func (s *Store) Add(spans ...*Span) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, span := range spans {
+		t, ok := s.traces[span.TraceID]
+		if !ok {
+			if evicted := s.ring[s.next]; evicted != "" {
+				delete(s.traces, evicted)
+			}
+			t = &storedTrace{id: span.TraceID}
+			s.traces[span.TraceID] = t
+			s.ring[s.next] = span.TraceID
+			s.next = (s.next + 1) % s.size
+		}
+		if len(t.spans) < MaxSpansPerTrace {
+			t.spans = append(t.spans, span)
+		}
+	}
+}
+
+// Spans returns a copy of the flat list of spans for the trace
+func (s *Store) Spans(traceID string) ([]*Span, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.traces[traceID]
+	if !ok {
+		return nil, false
+	}
+	spans := make([]*Span, len(t.spans))
+	copy(spans, t.spans)
+	return spans, true
+}
+
+type Summary struct {
+	TraceID   string    `json:"traceId"`
+	Root      string    `json:"root"`
+	Spans     int       `json:"spans"`
+	StartTime time.Time `json:"startTime"`
+	Duration  int64     `json:"durationMicros"`
+}
+
+// Summaries lists the stored traces, most recent first
+func (s *Store) Summaries() []Summary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	summaries := make([]Summary, 0, len(s.traces))
+	for _, t := range s.traces {
+		tree := BuildTree(t.id, t.spans)
+		summary := Summary{
+			TraceID:   t.id,
+			Spans:     len(t.spans),
+			StartTime: tree.StartTime,
+			Duration:  tree.EndTime.Sub(tree.StartTime).Microseconds(),
+		}
+		if len(tree.Roots) > 0 {
+			summary.Root = tree.Roots[0].Name
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].StartTime.After(summaries[j].StartTime)
+	})
+	return summaries
+}
+
+func fromSnapshot(snapshot *exporttrace.SpanSnapshot) *Span {
+	span := &Span{
+		TraceID:    snapshot.SpanContext.TraceID.String(),
+		SpanID:     snapshot.SpanContext.SpanID.String(),
+		Name:       snapshot.Name,
+		Kind:       snapshot.SpanKind.String(),
+		StartTime:  snapshot.StartTime,
+		EndTime:    snapshot.EndTime,
+		Attributes: make(map[string]string, len(snapshot.Attributes)),
+	}
+	if snapshot.ParentSpanID.IsValid() {
+		span.ParentSpanID = snapshot.ParentSpanID.String()
+	}
+	if snapshot.StatusCode != codes.Unset {
+		span.Status = snapshot.StatusCode.String()
+		span.StatusMessage = snapshot.StatusMessage
+	}
+	if snapshot.Resource != nil {
+		for _, kv := range snapshot.Resource.Attributes() {
+			if kv.Key == semconv.ServiceNameKey {
+				span.Service = kv.Value.Emit()
+			}
+		}
+	}
+	for _, kv := range snapshot.Attributes {
+		span.Attributes[string(kv.Key)] = kv.Value.Emit()
+	}
+	for _, e := range snapshot.MessageEvents {
+		event := Event{
+			Name: e.Name,
+			Time: e.Time,
+		}
+		if len(e.Attributes) > 0 {
+			event.Attributes = make(map[string]string, len(e.Attributes))
+			for _, kv := range e.Attributes {
+				event.Attributes[string(kv.Key)] = kv.Value.Emit()
+			}
+		}
+		span.Events = append(span.Events, event)
+	}
+	return span
+}
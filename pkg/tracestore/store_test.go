@@ -0,0 +1,61 @@
+package tracestore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func span(traceID string, spanID string, parentID string, start int, end int) *Span {
+	base := time.Unix(0, 0)
+	return &Span{
+		TraceID:      traceID,
+		SpanID:       spanID,
+		ParentSpanID: parentID,
+		Name:         spanID,
+		StartTime:    base.Add(time.Duration(start) * time.Millisecond),
+		EndTime:      base.Add(time.Duration(end) * time.Millisecond),
+	}
+}
+
+func TestRingBuffer(t *testing.T) {
+	store := NewStore(2)
+	store.Add(span("t1", "a", "", 0, 10))
+	store.Add(span("t2", "a", "", 0, 10))
+	store.Add(span("t2", "b", "a", 0, 10))
+	store.Add(span("t3", "a", "", 0, 10))
+
+	_, ok := store.Spans("t1")
+	assert.False(t, ok)
+	spans, ok := store.Spans("t2")
+	assert.True(t, ok)
+	assert.Len(t, spans, 2)
+	_, ok = store.Spans("t3")
+	assert.True(t, ok)
+	assert.Len(t, store.Summaries(), 2)
+}
+
+func TestBuildTree(t *testing.T) {
+	tree := BuildTree("t", []*Span{
+		span("t", "c", "b", 20, 40),
+		span("t", "a", "", 0, 100),
+		span("t", "b", "a", 10, 50),
+		span("t", "d", "a", 60, 90),
+		span("t", "x", "missing", 70, 80),
+	})
+
+	assert.Len(t, tree.Roots, 2)
+	assert.Equal(t, "a", tree.Roots[0].SpanID)
+	assert.Equal(t, "x", tree.Roots[1].SpanID)
+	assert.Len(t, tree.Roots[0].Children, 2)
+	assert.Equal(t, "b", tree.Roots[0].Children[0].SpanID)
+	assert.Equal(t, "d", tree.Roots[0].Children[1].SpanID)
+
+	bars := tree.Waterfall()
+	assert.Len(t, bars, 5)
+	assert.Equal(t, "c", bars[2].SpanID)
+	assert.Equal(t, 2, bars[2].Depth)
+	assert.InDelta(t, 20.0, bars[2].Offset, 0.001)
+	assert.InDelta(t, 20.0, bars[2].Width, 0.001)
+}
@@ -0,0 +1,85 @@
+package tracestore
+
+import (
+	"sort"
+	"time"
+)
+
+type Node struct {
+	*Span
+	DurationMicros int64   `json:"durationMicros"`
+	Children       []*Node `json:"children,omitempty"`
+}
+
+type Tree struct {
+	TraceID   string    `json:"traceId"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Spans     int       `json:"spans"`
+	Roots     []*Node   `json:"roots"`
+}
+
+// BuildTree links the spans of a trace to their parents. Spans of which the parent is not (yet)
+// received become a root, so an incomplete trace is still shown.
+func BuildTree(traceID string, spans []*Span) *Tree {
+	tree := &Tree{
+		TraceID: traceID,
+		Spans:   len(spans),
+	}
+	nodes := make(map[string]*Node, len(spans))
+	for _, span := range spans {
+		nodes[span.SpanID] = &Node{Span: span, DurationMicros: span.Duration().Microseconds()}
+		if tree.StartTime.IsZero() || span.StartTime.Before(tree.StartTime) {
+			tree.StartTime = span.StartTime
+		}
+		if span.EndTime.After(tree.EndTime) {
+			tree.EndTime = span.EndTime
+		}
+	}
+	for _, span := range spans {
+		node := nodes[span.SpanID]
+		if parent, ok := nodes[span.ParentSpanID]; ok && parent != node {
+			parent.Children = append(parent.Children, node)
+		} else {
+			tree.Roots = append(tree.Roots, node)
+		}
+	}
+	byStart := func(n []*Node) {
+		sort.SliceStable(n, func(i, j int) bool {
+			return n[i].StartTime.Before(n[j].StartTime)
+		})
+	}
+	byStart(tree.Roots)
+	for _, node := range nodes {
+		byStart(node.Children)
+	}
+	return tree
+}
+
+// Bar is a single line in the waterfall, offsets and widths are a percentage of the trace duration
+type Bar struct {
+	*Node
+	Depth  int
+	Offset float64
+	Width  float64
+}
+
+// Waterfall flattens the tree depth first
+func (t *Tree) Waterfall() []Bar {
+	total := t.EndTime.Sub(t.StartTime)
+	var bars []Bar
+	var walk func(nodes []*Node, depth int)
+	walk = func(nodes []*Node, depth int) {
+		for _, node := range nodes {
+			bar := Bar{Node: node, Depth: depth, Width: 100}
+			if total > 0 {
+				bar.Offset = float64(node.StartTime.Sub(t.StartTime)) / float64(total) * 100
+				bar.Width = float64(node.Duration()) / float64(total) * 100
+			}
+			bars = append(bars, bar)
+			walk(node.Children, depth+1)
+		}
+	}
+	walk(t.Roots, 0)
+	return bars
+}
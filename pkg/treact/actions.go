@@ -2,36 +2,89 @@ package treact
 
 import (
 	"context"
-	"fmt"
+	"github.com/treactor/treactor-go/pkg/element"
+	"github.com/treactor/treactor-go/pkg/execute"
 	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"strconv"
 	"time"
 )
 
-func mem(ctx context.Context, factorValue string) []byte {
+// actionEvent records an injected action as an event on the span of the atom
+func actionEvent(ctx context.Context, action string, value string, attributes ...attribute.KeyValue) {
+	attributes = append([]attribute.KeyValue{
+		resource.ActionKey.String(action),
+		resource.ActionValueKey.String(value),
+	}, attributes...)
+	trace.SpanFromContext(ctx).AddEvent(action, trace.WithAttributes(attributes...))
+}
+
+// sleep waits for the duration (milliseconds or with a unit, like 1.5s) or till the request is cancelled
+func sleep(ctx context.Context, durationValue string) {
+	duration, err := resource.ParseDuration(durationValue)
+	if err != nil || duration < 0 {
+		resource.Logger.WarningF(ctx, "Ignoring sleep action: sleep needs a duration, like 500ms")
+		return
+	}
+	start := time.Now()
+	select {
+	case <-ctx.Done():
+	case <-time.After(duration):
+	}
+	actionEvent(ctx, "sleep", durationValue, attribute.Int64("treactor.sleep.elapsed_ms", time.Now().Sub(start).Milliseconds()))
+}
 
-	factor, _ := strconv.ParseFloat(factorValue,32)
+// fail returns true with the given probability in percent, fail:100 always fails. The outcome is drawn from the
+// seed and hop path of the reaction.
+func fail(ctx context.Context, percentValue string) bool {
+	percent, err := strconv.ParseFloat(percentValue, 64)
+	if err != nil || percent < 0 || percent > 100 {
+		resource.Logger.WarningF(ctx, "Ignoring fail action: fail needs a percentage between 0 and 100")
+		return false
+	}
+	failed := execute.Rand(ctx, "fail").Float64()*100 < percent
+	actionEvent(ctx, "fail", percentValue, attribute.Bool("treactor.fail.failed", failed))
+	return failed
+}
 
-	size := factor * 1024 * 1024
+// maxSpans limits the synthetic spans of a call, they are created inside the request
+const maxSpans = 100 * 1000
 
-	return make([]byte, int(size))
+// spans creates N synthetic child spans, to load test trace backends
+func spans(ctx context.Context, countValue string) {
+	count, err := strconv.Atoi(countValue)
+	if err != nil || count < 0 || count > maxSpans {
+		resource.Logger.WarningF(ctx, "Ignoring spans action: spans needs a number of spans up to %d", maxSpans)
+		return
+	}
+	for i := 0; i < count; i++ {
+		_, span := resource.Tracer.Start(ctx, "Synthetic", trace.WithAttributes(
+			attribute.Int("treactor.synthetic.index", i)))
+		span.End()
+	}
+	actionEvent(ctx, "spans", countValue)
 }
 
-func cpu(ctx context.Context, durationValue string) {
-	duration, _ := strconv.ParseInt(durationValue, 10, 64)
-	start := time.Now()
-	for ; true; {
-		elapsed := time.Now().Sub(start)
-		select {
-		case <- ctx.Done():
-			resource.Logger.WarningF(ctx, "CPU Action cancelled after %dms (%dms)", elapsed, duration)
-			return
-		default:
-			// nothing
-		}
-		if elapsed.Milliseconds() > duration {
-			return
-		}
-		fmt.Println(ctx.Err())
+// behave applies the behavior profile of the element: latency by weight, extra logs of reactive atoms and the
+// decay of radioactive ones. It returns true when the atom decayed.
+func behave(ctx context.Context, atom element.Atom) bool {
+	behavior := &resource.Atoms.Behavior
+	latency := behavior.Latency(atom)
+	select {
+	case <-ctx.Done():
+	case <-time.After(latency):
+	}
+	logs := behavior.ExtraLogs(atom)
+	if logs > 0 {
+		(&resource.LogVolume{Count: logs, Severity: "INFO", Format: "plain"}).Emit(ctx)
 	}
+	percent := behavior.DecayPercent(atom)
+	decayed := percent > 0 && execute.Rand(ctx, "decay").Float64()*100 < percent
+	actionEvent(ctx, "behavior", atom.Category.String(),
+		attribute.Int64("treactor.behavior.latency_ms", latency.Milliseconds()),
+		attribute.Int("treactor.behavior.logs", logs),
+		attribute.Float64("treactor.behavior.decay_percent", percent),
+		attribute.Bool("treactor.behavior.decayed", decayed))
+	return decayed
 }
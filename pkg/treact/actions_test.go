@@ -0,0 +1,103 @@
+package treact
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/treactor/treactor-go/pkg/execute"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"github.com/treactor/treactor-go/pkg/tracestore"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFail(t *testing.T) {
+	for _, test := range []struct {
+		percent  string
+		expected bool
+	}{
+		{"0", false},
+		{"100", true},
+		{"x", false},
+		{"150", false},
+	} {
+		for seed := int64(1); seed <= 20; seed++ {
+			assert.Equal(t, test.expected, fail(execute.WithSeed(context.Background(), seed), test.percent), test.percent)
+		}
+	}
+
+	// the outcome is drawn from the seed, the same seed fails the same way
+	ctx := execute.WithSeed(context.Background(), 42)
+	assert.Equal(t, fail(ctx, "50"), fail(ctx, "50"))
+}
+
+func TestSleep(t *testing.T) {
+	for _, test := range []struct {
+		value   string
+		timeout time.Duration
+		least   time.Duration
+		most    time.Duration
+	}{
+		{"50", time.Hour, 50 * time.Millisecond, time.Hour},
+		{"50ms", time.Hour, 50 * time.Millisecond, time.Hour},
+		{"0.05s", time.Hour, 50 * time.Millisecond, time.Hour},
+		{"0", time.Hour, 0, time.Second},
+		// a malformed duration is ignored
+		{"soon", time.Hour, 0, time.Second},
+		{"-1s", time.Hour, 0, time.Second},
+		// the cancelled request stops the sleep
+		{"60000", 20 * time.Millisecond, 20 * time.Millisecond, 10 * time.Second},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), test.timeout)
+		start := time.Now()
+		sleep(ctx, test.value)
+		elapsed := time.Now().Sub(start)
+		cancel()
+		assert.GreaterOrEqual(t, int64(elapsed), int64(test.least), test.value)
+		assert.Less(t, int64(elapsed), int64(test.most), test.value)
+	}
+}
+
+func TestInjectedFailure(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/treact/atoms/h?symbol=H,fail:100", nil)
+	injectedFailure(r.Context(), w, r, "Atom Hydrogen failed (fail:100)")
+	assert.Equal(t, 500, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var response ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.InsertId)
+}
+
+func TestSpans(t *testing.T) {
+	defer func(tracer trace.Tracer) { resource.Tracer = tracer }(resource.Tracer)
+	store := tracestore.NewStore(10)
+	resource.Tracer = sdktrace.NewTracerProvider(sdktrace.WithSyncer(store)).Tracer("test")
+
+	for _, test := range []struct {
+		value    string
+		expected int
+	}{
+		{"3", 3},
+		{"0", 0},
+		{"-1", 0},
+		{"1000001", 0},
+		{"many", 0},
+	} {
+		ctx, parent := resource.Tracer.Start(context.Background(), "Atom")
+		spans(ctx, test.value)
+		parent.End()
+		recorded, _ := store.Spans(parent.SpanContext().TraceID.String())
+		synthetic := 0
+		for _, span := range recorded {
+			if span.Name == "Synthetic" {
+				synthetic++
+			}
+		}
+		assert.Equal(t, test.expected, synthetic, test.value)
+	}
+}
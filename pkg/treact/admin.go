@@ -0,0 +1,102 @@
+package treact
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/execute"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// serveAdmin serves the admin API on its own port, it's only there with a TREACTOR_ADMIN_TOKEN
+func serveAdmin() {
+	if resource.AdminToken == "" {
+		return
+	}
+	admin := http.NewServeMux()
+	admin.Handle("/admin/settings", authorized(http.HandlerFunc(TReactAdminSettingsHandle)))
+	go func() {
+		log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", resource.AdminPort), admin))
+	}()
+	fmt.Printf("Admin: http://localhost:%s/admin/settings\n", resource.AdminPort)
+}
+
+// authorized lets the requests with the bearer token of TREACTOR_ADMIN_TOKEN through
+func authorized(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(resource.AdminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="treactor"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TReactAdminSettingsHandle answers the runtime settings, a PUT or PATCH with some of them changes them:
+//
+//	{"latency": "200ms", "errorRate": 10, "ready": false, "logLevel": "warning", "sampler": "traceidratio:0.1"}
+func TReactAdminSettingsHandle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+	case "PUT", "PATCH":
+		var update resource.SettingsUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, fmt.Sprintf("Malformed settings: %s", err), http.StatusBadRequest)
+			return
+		}
+		if _, err := resource.UpdateSettings(update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		settings, _ := json.Marshal(update)
+		resource.Logger.WarningF(r.Context(), "Admin changed the settings: %s", settings)
+	default:
+		w.Header().Set("Allow", "GET, PUT, PATCH")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// all settings are shown, also the ones at their zero value like ready false
+	bytes, _ := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(settingsProto(resource.CurrentSettings()))
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bytes)
+}
+
+func settingsProto(settings resource.Settings) *treactorpb.Settings {
+	return &treactorpb.Settings{
+		LatencyMs: settings.Latency.Milliseconds(),
+		ErrorRate: settings.ErrorRate,
+		Ready:     settings.Ready,
+		LogLevel:  settings.LogLevel,
+		Sampler:   settings.Sampler,
+	}
+}
+
+// degrade applies the latency and error rate of the settings to an atom or bond, it returns true when it fails.
+// The failure is drawn from the seed and hop path of the reaction.
+func degrade(ctx context.Context) bool {
+	settings := resource.CurrentSettings()
+	if settings.Latency <= 0 && settings.ErrorRate <= 0 {
+		return false
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(settings.Latency):
+	}
+	failed := settings.ErrorRate > 0 && execute.Rand(ctx, "degrade").Float64()*100 < settings.ErrorRate
+	actionEvent(ctx, "degrade", "settings",
+		attribute.Int64("treactor.degrade.latency_ms", settings.Latency.Milliseconds()),
+		attribute.Float64("treactor.degrade.error_rate", settings.ErrorRate),
+		attribute.Bool("treactor.degrade.failed", failed))
+	return failed
+}
@@ -0,0 +1,43 @@
+package treact
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestAdminSettings(t *testing.T) {
+	resource.AdminToken = "secret"
+	defer func() { resource.AdminToken = "" }()
+	ready, latency := true, "0"
+	defer resource.UpdateSettings(resource.SettingsUpdate{Ready: &ready, Latency: &latency})
+	handler := authorized(http.HandlerFunc(TReactAdminSettingsHandle))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/admin/settings", nil))
+	assert.Equal(t, 401, w.Code)
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/admin/settings", strings.NewReader(`{"ready":false,"latency":"20ms"}`))
+	r.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	settings := &treactorpb.Settings{}
+	assert.NoError(t, protojson.Unmarshal(w.Body.Bytes(), settings))
+	assert.False(t, settings.Ready)
+	assert.Equal(t, int64(20), settings.LatencyMs)
+
+	assert.False(t, resource.CurrentSettings().Ready)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("PUT", "/admin/settings", strings.NewReader(`{"logLevel":"debug"}`))
+	r.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, 400, w.Code)
+}
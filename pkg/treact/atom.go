@@ -0,0 +1,43 @@
+package treact
+
+import (
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/element"
+)
+
+func atomProto(atom element.Atom) *treactorpb.Atom {
+	pb := &treactorpb.Atom{
+		Number:            atom.Number,
+		Symbol:            atom.Symbol,
+		Name:              atom.Name,
+		Period:            &atom.Period,
+		Origin:            atom.Origin,
+		Category:          atom.Category.String(),
+		Weight:            quantityProto(atom.Weight),
+		Density:           quantityProto(atom.Density),
+		Melt:              quantityProto(atom.Melt),
+		Boil:              quantityProto(atom.Boil),
+		HeatCapacity:      quantityProto(atom.HeatCapacity),
+		Electronegativity: quantityProto(atom.Electronegativity),
+		Abundance:         quantityProto(atom.Abundance),
+	}
+	// the lanthanides and actinides have no group
+	if atom.Group > 0 {
+		pb.Group = &atom.Group
+	}
+	return pb
+}
+
+func quantityProto(q *element.Quantity) *treactorpb.Quantity {
+	if q == nil {
+		return nil
+	}
+	return &treactorpb.Quantity{
+		Value:       q.Value,
+		Uncertainty: q.Uncertainty,
+		Estimated:   q.Estimated,
+		MassNumber:  q.MassNumber,
+		Approximate: q.Approximate,
+		UpperBound:  q.UpperBound,
+	}
+}
@@ -0,0 +1,130 @@
+package treact
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/collector"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// maxCheckWait is the longest a check waits for the spans
+const maxCheckWait = time.Minute
+
+// TReactCheckHandle verifies that every hop of a reaction made it into the trace.
+//
+// GET runs the molecule against the collector target, POST takes the response of a reaction
+// that was run elsewhere as body. In both cases the spans are awaited for at most `wait` (up to
+// a minute) before the report is made, or till the client goes away.
+func TReactCheckHandle(w http.ResponseWriter, r *http.Request) {
+	wait, err := checkDuration(r, "wait", 5*time.Second)
+	if err == nil && wait > maxCheckWait {
+		err = fmt.Errorf("wait is at most %s", maxCheckWait)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tolerance, err := checkDuration(r, "skew", 10*time.Millisecond)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var node *treactorpb.Node
+	var traceID string
+	if r.Method == http.MethodPost {
+		if node, err = readNode(r.Body); err != nil {
+			http.Error(w, fmt.Sprintf("malformed reaction: %s", err), http.StatusBadRequest)
+			return
+		}
+		traceID = r.URL.Query().Get("trace")
+		if traceID == "" {
+			traceID, _, _ = collector.TraceParent(node)
+		}
+	} else if traceID, node, err = runReaction(r.Context(), r.URL.Query().Get("molecule")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if traceID == "" {
+		http.Error(w, "no trace id, pass it with ?trace=", http.StatusBadRequest)
+		return
+	}
+
+	deadline := time.Now().Add(wait)
+	spans, _ := resource.TraceStore.Spans(traceID)
+	for !collector.Received(node, spans) && time.Now().Before(deadline) {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+		spans, _ = resource.TraceStore.Spans(traceID)
+	}
+	writeJson(w, collector.Check(traceID, node, spans, tolerance))
+}
+
+// checkDuration parses the duration of the query parameter, like 5s, without it the fallback is returned
+func checkDuration(r *http.Request, name string, fallback time.Duration) (time.Duration, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("malformed %s %s, like %s", name, value, fallback)
+	}
+	return d, nil
+}
+
+func runReaction(ctx context.Context, molecule string) (string, *treactorpb.Node, error) {
+	ctx, span := resource.Tracer.Start(ctx, "Check", trace.WithNewRoot())
+	defer span.End()
+
+	target := fmt.Sprintf("%s%s/reactions?molecule=%s", resource.CollectorTarget, resource.Base, url.QueryEscape(molecule))
+	req, _ := http.NewRequestWithContext(ctx, "GET", target, nil)
+	resp, err := resource.HttpClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	node, err := readNode(resp.Body)
+	return span.SpanContext().TraceID.String(), node, err
+}
+
+func readNode(body io.Reader) (*treactorpb.Node, error) {
+	bytes, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	node := &treactorpb.Node{}
+	if err := protojson.Unmarshal(bytes, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// serveCollector starts the OTLP/gRPC and OTLP/HTTP receivers, they feed the trace store
+func serveCollector(r *http.ServeMux) {
+	receiver := collector.NewReceiver(resource.TraceStore)
+	go func() {
+		log.Fatal(receiver.ServeGRPC(resource.CollectorGrpcPort))
+	}()
+	otlpHttp := http.NewServeMux()
+	otlpHttp.Handle("/v1/traces", receiver)
+	go func() {
+		log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", resource.CollectorHttpPort), otlpHttp))
+	}()
+	r.HandleFunc(fmt.Sprintf("%s/check", resource.Base), TReactCheckHandle)
+
+	fmt.Printf("Collector: OTLP/gRPC on port %s, OTLP/HTTP on port %s\n", resource.CollectorGrpcPort, resource.CollectorHttpPort)
+}
@@ -0,0 +1,43 @@
+package treact
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"github.com/treactor/treactor-go/pkg/tracestore"
+)
+
+func TestCheckMalformedReaction(t *testing.T) {
+	w := httptest.NewRecorder()
+	TReactCheckHandle(w, httptest.NewRequest("POST", "/treact/check", strings.NewReader("{not a node")))
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "malformed reaction")
+}
+
+func TestCheckQuery(t *testing.T) {
+	for _, query := range []string{"wait=soon", "wait=24h", "wait=-1s", "skew=x"} {
+		w := httptest.NewRecorder()
+		TReactCheckHandle(w, httptest.NewRequest("GET", "/treact/check?molecule=[H]&"+query, nil))
+		assert.Equal(t, 400, w.Code, query)
+	}
+}
+
+func TestCheckCancelled(t *testing.T) {
+	store := resource.TraceStore
+	resource.TraceStore = tracestore.NewStore(10)
+	defer func() { resource.TraceStore = store }()
+
+	// the spans of the hop never arrive, the check stops waiting when the client goes away
+	node := `{"request": {"headers": {"Traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}}}`
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	r := httptest.NewRequest("POST", "/treact/check?wait=1m", strings.NewReader(node)).WithContext(ctx)
+	start := time.Now()
+	TReactCheckHandle(httptest.NewRecorder(), r)
+	assert.Less(t, time.Since(start).Milliseconds(), int64(30*1000))
+}
@@ -0,0 +1,127 @@
+package treact
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/treactor/treactor-go/pkg/pi"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// dutyPeriod is the period of the duty cycle, a core at 30% burns 30ms and idles 70ms of every period
+const dutyPeriod = 100 * time.Millisecond
+
+// piChunk is the number of terms computed between the checks of the clock and the context
+const piChunk = 10000
+
+const maxCores = 256
+
+// cpuSpec is the cpu KV annotation:
+//
+//	cpu:D                 burn one core for D (milliseconds or with a unit, like 500ms)
+//	cpu:D@C               burn C cores for D, like cpu:500ms@2
+//	cpu:D@P%              burn one core at a duty cycle of P percent, like cpu:2s@50%
+//	cpu:D@CxP%            burn C cores at a duty cycle of P percent, like cpu:500ms@2x50%
+type cpuSpec struct {
+	Duration time.Duration
+	Cores    int
+	Duty     float64
+}
+
+func parseCpu(value string) (*cpuSpec, error) {
+	spec := strings.SplitN(value, "@", 2)
+	duration, err := resource.ParseDuration(spec[0])
+	if err != nil || duration < 0 {
+		return nil, fmt.Errorf("cpu needs a duration")
+	}
+	c := &cpuSpec{Duration: duration, Cores: 1, Duty: 100}
+	if len(spec) == 1 {
+		return c, nil
+	}
+	option := spec[1]
+	if strings.HasSuffix(option, "%") {
+		cores := "1"
+		if i := strings.Index(option, "x"); i >= 0 {
+			cores, option = option[:i], option[i+1:]
+		}
+		c.Duty, err = strconv.ParseFloat(strings.TrimSuffix(option, "%"), 64)
+		if err != nil || c.Duty <= 0 || c.Duty > 100 {
+			return nil, fmt.Errorf("cpu duty cycle needs a percentage between 0 and 100")
+		}
+		option = cores
+	}
+	c.Cores, err = strconv.Atoi(option)
+	if err != nil || c.Cores < 1 || c.Cores > maxCores {
+		return nil, fmt.Errorf("cpu needs a number of cores between 1 and %d", maxCores)
+	}
+	return c, nil
+}
+
+// cpu burns the cores computing pi till the duration passed or the request is cancelled
+func cpu(ctx context.Context, value string) {
+	c, err := parseCpu(value)
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Ignoring cpu action: %s", err)
+		return
+	}
+	start := time.Now()
+	deadline := start.Add(c.Duration)
+	terms := make([]int, c.Cores)
+	wg := sync.WaitGroup{}
+	wg.Add(c.Cores)
+	for i := 0; i < c.Cores; i++ {
+		i := i
+		go pprof.Do(ctx, pprof.Labels("treactor.action", "cpu"), func(ctx context.Context) {
+			defer wg.Done()
+			terms[i] = burn(ctx, deadline, c.Duty)
+		})
+	}
+	wg.Wait()
+	total := 0
+	for _, n := range terms {
+		total += n
+	}
+	elapsed := time.Now().Sub(start)
+	if ctx.Err() != nil {
+		resource.Logger.WarningF(ctx, "CPU Action cancelled after %dms (%dms)", elapsed.Milliseconds(), c.Duration.Milliseconds())
+	}
+	actionEvent(ctx, "cpu", value,
+		attribute.Int64("treactor.cpu.elapsed_ms", elapsed.Milliseconds()),
+		attribute.Int("treactor.cpu.cores", c.Cores),
+		attribute.Float64("treactor.cpu.duty_percent", c.Duty),
+		attribute.Int("treactor.cpu.terms", total))
+}
+
+// burn computes terms of the pi series for the duty cycle of every period till the deadline, it returns the
+// number of terms
+func burn(ctx context.Context, deadline time.Time, duty float64) int {
+	k := 0
+	for {
+		periodStart := time.Now()
+		if !periodStart.Before(deadline) || ctx.Err() != nil {
+			return k
+		}
+		busyUntil := periodStart.Add(time.Duration(float64(dutyPeriod) * duty / 100))
+		for time.Now().Before(busyUntil) && time.Now().Before(deadline) && ctx.Err() == nil {
+			pi.Partial(k, k+piChunk)
+			k += piChunk
+		}
+		if duty >= 100 {
+			continue
+		}
+		idleUntil := periodStart.Add(dutyPeriod)
+		if idleUntil.After(deadline) {
+			idleUntil = deadline
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Until(idleUntil)):
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package treact
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCpu(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		expected cpuSpec
+	}{
+		{"50", cpuSpec{Duration: 50 * time.Millisecond, Cores: 1, Duty: 100}},
+		{"500ms@2", cpuSpec{Duration: 500 * time.Millisecond, Cores: 2, Duty: 100}},
+		{"2s@25%", cpuSpec{Duration: 2 * time.Second, Cores: 1, Duty: 25}},
+		{"500ms@2x50%", cpuSpec{Duration: 500 * time.Millisecond, Cores: 2, Duty: 50}},
+	} {
+		c, err := parseCpu(test.in)
+		assert.NoError(t, err, test.in)
+		assert.Equal(t, test.expected, *c, test.in)
+	}
+
+	for _, in := range []string{"x", "500ms@0", "500ms@2x0%", "500ms@2x150%", "500ms@x50%", "1s@1000"} {
+		_, err := parseCpu(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestBurn(t *testing.T) {
+	start := time.Now()
+	assert.Greater(t, burn(context.Background(), start.Add(150*time.Millisecond), 50), 0)
+	assert.GreaterOrEqual(t, time.Since(start).Milliseconds(), int64(150))
+
+	// a cancelled request stops burning long before the deadline, also in the idle part of the duty cycle
+	for _, duty := range []float64{100, 10} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		start = time.Now()
+		burn(ctx, start.Add(time.Minute), duty)
+		cancel()
+		assert.Less(t, time.Since(start).Milliseconds(), int64(30*1000), duty)
+	}
+}
@@ -0,0 +1,69 @@
+package treact
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// crashTimeout is how long the telemetry gets to flush before the process exits
+const crashTimeout = 2 * time.Second
+
+// destructive tells if a destructive action can run, without TREACTOR_ALLOW_DESTRUCTIVE=1 it's ignored
+func destructive(ctx context.Context, action string) bool {
+	if !resource.AllowDestructive {
+		resource.Logger.WarningF(ctx, "Ignoring %s action, set TREACTOR_ALLOW_DESTRUCTIVE=1 to allow it", action)
+		actionEvent(ctx, action, "ignored")
+		return false
+	}
+	return true
+}
+
+// enabled tells if a flag action like panic:1 is on
+func enabled(value string) bool {
+	on, _ := strconv.ParseBool(value)
+	return on
+}
+
+// panicking panics in the handler, the recovery middleware turns it into a 500
+func panicking(ctx context.Context, atom string, value string) {
+	if !enabled(value) {
+		return
+	}
+	actionEvent(ctx, "panic", value)
+	panic(fmt.Sprintf("Atom %s panicked (panic:%s)", atom, value))
+}
+
+// crash exits the process with the exit code, after flushing the telemetry
+func crash(ctx context.Context, atom string, value string) {
+	code, err := strconv.Atoi(value)
+	if err != nil || code < 1 || code > 255 {
+		resource.Logger.WarningF(ctx, "Ignoring crash action: crash needs an exit code between 1 and 255")
+		return
+	}
+	if !destructive(ctx, "crash") {
+		return
+	}
+	actionEvent(ctx, "crash", value, attribute.Int("treactor.crash.exit_code", code))
+	resource.Logger.ErrorF(ctx, nil, "Atom %s crashes with exit code %d (crash:%s)", atom, code, value)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), crashTimeout)
+	defer cancel()
+	resource.Shutdown(shutdownCtx)
+	os.Exit(code)
+}
+
+// hang doesn't respond till the client gives up, it returns true when it hung and the atom can't answer anymore
+func hang(ctx context.Context, value string) bool {
+	if !enabled(value) || !destructive(ctx, "hang") {
+		return false
+	}
+	start := time.Now()
+	<-ctx.Done()
+	actionEvent(ctx, "hang", value, attribute.Int64("treactor.hang.elapsed_ms", time.Now().Sub(start).Milliseconds()))
+	return true
+}
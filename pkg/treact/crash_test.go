@@ -0,0 +1,40 @@
+package treact
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/treactor/treactor-go/pkg/resource"
+)
+
+func TestRecovery(t *testing.T) {
+	handler := recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panicking(r.Context(), "Hydrogen", "1")
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/treact/atoms/h?symbol=H,panic:1", nil))
+	assert.Equal(t, 500, w.Code)
+	assert.Contains(t, w.Body.String(), "InsertId")
+
+	assert.Panics(t, func() {
+		recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	})
+}
+
+func TestDestructiveNeedsFlag(t *testing.T) {
+	resource.AllowDestructive = false
+	assert.False(t, hang(context.Background(), "1"))
+
+	resource.AllowDestructive = true
+	defer func() { resource.AllowDestructive = false }()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.False(t, hang(ctx, "0"))
+	assert.True(t, hang(ctx, "1"))
+}
@@ -0,0 +1,43 @@
+package treact
+
+import (
+	"bytes"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/treactor/treactor-go/pkg/element"
+	"github.com/treactor/treactor-go/pkg/resource"
+)
+
+// TReactElementsHandle searches the periodic table with the criteria of the selectors:
+// /treact/elements?group=18&period=2&property=noble_gas&number=1..10
+func TReactElementsHandle(w http.ResponseWriter, r *http.Request) {
+	ctx, span := resource.StartSpan(r.Context(), resource.GranularityHop, "TReactElementsHandle")
+	defer span.End()
+
+	criteria := make(map[string]string)
+	for key := range r.URL.Query() {
+		if element.IsQueryKey(key) {
+			criteria[key] = r.URL.Query().Get(key)
+		}
+	}
+	query, err := element.ParseQuery(criteria)
+	if err != nil {
+		failure(ctx, w, r, "Unable to parse query", err)
+		return
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString("[")
+	for i, atom := range resource.Atoms.Select(query) {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		b, _ := protojson.Marshal(atomProto(atom))
+		buffer.Write(b)
+	}
+	buffer.WriteString("]")
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buffer.Bytes())
+}
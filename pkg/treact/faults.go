@@ -0,0 +1,198 @@
+package treact
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// paddingHeaderSize is the size of a padding header of the headers action
+	paddingHeaderSize = 1024
+	maxHeaderBytes    = 64 * 1024 * 1024
+	// dripSteps is the number of pieces a dripped body is written in
+	dripSteps = 20
+)
+
+// faultWriter injects the transport faults of the KV annotations in the response of the atom:
+//
+//	reset:1               hijack the connection and reset it halfway the body
+//	drip:D                write the body in pieces spread over D
+//	headers:N             add N bytes of padding headers (k and m suffixes allowed)
+//	truncate:P            announce the full body but only write P percent of it, then close the connection
+//
+// The announced Content-Length is the full body, the client sees the difference.
+type faultWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	reset       bool
+	drip        time.Duration
+	headerBytes int
+	truncate    float64
+	wroteHeader bool
+}
+
+// newFaultWriter wraps the response writer, the writer itself is returned when there are no faults
+func newFaultWriter(ctx context.Context, w http.ResponseWriter, kv map[string]string) http.ResponseWriter {
+	fw := &faultWriter{ResponseWriter: w, ctx: ctx}
+	var err error
+	if kv["reset"] != "" {
+		fw.reset = enabled(kv["reset"])
+	}
+	if kv["drip"] != "" {
+		if fw.drip, err = resource.ParseDuration(kv["drip"]); err != nil {
+			resource.Logger.WarningF(ctx, "Ignoring drip action: %s", err)
+		}
+	}
+	if kv["headers"] != "" {
+		fw.headerBytes, err = resource.ParseSize(kv["headers"])
+		if err != nil || fw.headerBytes > maxHeaderBytes {
+			resource.Logger.WarningF(ctx, "Ignoring headers action: headers needs a size up to %d bytes", maxHeaderBytes)
+			fw.headerBytes = 0
+		}
+	}
+	if kv["truncate"] != "" {
+		fw.truncate, err = strconv.ParseFloat(strings.TrimSuffix(kv["truncate"], "%"), 64)
+		if err != nil || fw.truncate < 0 || fw.truncate >= 100 {
+			resource.Logger.WarningF(ctx, "Ignoring truncate action: truncate needs a percentage below 100")
+			fw.truncate = 0
+		}
+	}
+	if !fw.reset && fw.drip <= 0 && fw.headerBytes <= 0 && fw.truncate <= 0 {
+		return w
+	}
+	return fw
+}
+
+// mangles tells if the body is written differently, the full length is announced up front then
+func (w *faultWriter) mangles() bool {
+	return w.reset || w.drip > 0 || w.truncate > 0
+}
+
+func (w *faultWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if w.headerBytes > 0 {
+		padding := strings.Repeat("x", paddingHeaderSize)
+		for i := 0; i*paddingHeaderSize < w.headerBytes; i++ {
+			w.Header().Set(fmt.Sprintf("X-Treactor-Padding-%d", i), padding)
+		}
+		actionEvent(w.ctx, "headers", strconv.Itoa(w.headerBytes))
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *faultWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.mangles() {
+			w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	switch {
+	case w.reset:
+		n, _ := w.ResponseWriter.Write(b[:len(b)/2])
+		actionEvent(w.ctx, "reset", "1", attribute.Int("treactor.reset.written", n))
+		w.resetConnection()
+		return n, http.ErrHijacked
+	case w.truncate > 0:
+		n, _ := w.ResponseWriter.Write(b[:int(float64(len(b))*w.truncate/100)])
+		actionEvent(w.ctx, "truncate", strconv.FormatFloat(w.truncate, 'f', -1, 64), attribute.Int("treactor.truncate.written", n))
+		w.flush()
+		// the server closes the connection without completing the response
+		panic(http.ErrAbortHandler)
+	case w.drip > 0:
+		return w.dripWrite(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *faultWriter) flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// resetConnection hijacks the connection and closes it with a RST instead of a FIN
+func (w *faultWriter) resetConnection() {
+	w.flush()
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	conn.Close()
+}
+
+// dripWrite writes the body in pieces, flushed one by one and spread over the drip duration
+func (w *faultWriter) dripWrite(b []byte) (int, error) {
+	start := time.Now()
+	step := (len(b) + dripSteps - 1) / dripSteps
+	written := 0
+	for written < len(b) {
+		end := written + step
+		if end > len(b) {
+			end = len(b)
+		}
+		n, err := w.ResponseWriter.Write(b[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		w.flush()
+		if written == len(b) {
+			break
+		}
+		select {
+		case <-w.ctx.Done():
+			return written, w.ctx.Err()
+		case <-time.After(w.drip / dripSteps):
+		}
+	}
+	actionEvent(w.ctx, "drip", w.drip.String(), attribute.Int64("treactor.drip.elapsed_ms", time.Now().Sub(start).Milliseconds()))
+	return written, nil
+}
+
+// redirect answers with a redirect to the atom itself with one redirect less, redirect:N makes the client follow
+// N redirects. The status code is 302 or set like redirect:3@307. It returns false when there are no redirects left.
+func redirect(ctx context.Context, w http.ResponseWriter, r *http.Request, value string) bool {
+	spec := strings.SplitN(value, "@", 2)
+	count, err := strconv.Atoi(spec[0])
+	code := http.StatusFound
+	if err == nil && len(spec) == 2 {
+		code, err = strconv.Atoi(spec[1])
+		if err == nil && (code < 300 || code > 308) {
+			err = fmt.Errorf("redirect needs a 3xx status code")
+		}
+	}
+	if err != nil || count < 0 {
+		resource.Logger.WarningF(ctx, "Ignoring redirect action: redirect needs a number of redirects")
+		return false
+	}
+	if count == 0 {
+		return false
+	}
+	next := strings.Replace(value, spec[0], strconv.Itoa(count-1), 1)
+	query := r.URL.Query()
+	query.Set("symbol", strings.Replace(query.Get("symbol"), "redirect:"+value, "redirect:"+next, 1))
+	location := *r.URL
+	location.RawQuery = query.Encode()
+	actionEvent(ctx, "redirect", value, attribute.Int("treactor.redirect.status_code", code))
+	http.Redirect(w, r, location.String(), code)
+	return true
+}
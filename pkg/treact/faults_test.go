@@ -0,0 +1,93 @@
+package treact
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/treactor/treactor-go/pkg/execute"
+)
+
+// faultServer answers a body of 10k with the faults of the KV annotations
+func faultServer(kv map[string]string) *httptest.Server {
+	return httptest.NewServer(recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w = newFaultWriter(r.Context(), w, kv)
+		w.Write([]byte(strings.Repeat("x", 10*1024)))
+	})))
+}
+
+// get reads the body, the error is the one of the request or of reading the body
+func get(url string) (*http.Response, []byte, error) {
+	response, err := http.Get(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	return response, body, err
+}
+
+func TestNoFaults(t *testing.T) {
+	w := httptest.NewRecorder()
+	assert.Equal(t, w, newFaultWriter(context.Background(), w, map[string]string{"sleep": "10"}))
+}
+
+func TestFaults(t *testing.T) {
+
+	server := faultServer(map[string]string{"truncate": "50"})
+	_, body, err := get(server.URL)
+	server.Close()
+	assert.Error(t, err)
+	assert.Equal(t, 5*1024, len(body))
+	assert.Equal(t, "truncated", execute.Fault(err))
+
+	server = faultServer(map[string]string{"reset": "1"})
+	_, _, err = get(server.URL)
+	server.Close()
+	assert.Error(t, err)
+	assert.Contains(t, []string{"reset", "truncated"}, execute.Fault(err))
+
+	server = faultServer(map[string]string{"headers": "64k"})
+	response, body, err := get(server.URL)
+	server.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 10*1024, len(body))
+	assert.Equal(t, strings.Repeat("x", paddingHeaderSize), response.Header.Get("X-Treactor-Padding-63"))
+	assert.Empty(t, response.Header.Get("X-Treactor-Padding-64"))
+
+	server = faultServer(map[string]string{"drip": "200ms"})
+	start := time.Now()
+	_, body, err = get(server.URL)
+	server.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 10*1024, len(body))
+	assert.GreaterOrEqual(t, int64(time.Now().Sub(start)), int64(190*time.Millisecond))
+}
+
+func TestRedirect(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.URL.Query().Get("symbol")
+		calls = append(calls, symbol)
+		if redirect(r.Context(), w, r, strings.SplitN(symbol, "redirect:", 2)[1]) {
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	response, body, err := get(server.URL + "/treact/atoms/h?symbol=H,redirect:2@307")
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, []string{"H,redirect:2@307", "H,redirect:1@307", "H,redirect:0@307"}, calls)
+	assert.Equal(t, 307, response.Request.Response.StatusCode)
+
+	_, _, err = get(server.URL + "/treact/atoms/h?symbol=H,redirect:12")
+	assert.Error(t, err)
+	assert.Equal(t, "redirects", execute.Fault(err))
+}
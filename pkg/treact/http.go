@@ -5,24 +5,37 @@ import (
 	"encoding/json"
 	"fmt"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
-	"github.com/treactor/treactor-go/pkg/element"
 	"github.com/treactor/treactor-go/pkg/execute"
 	"github.com/treactor/treactor-go/pkg/resource"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	trace "go.opentelemetry.io/otel/trace"
 	"log"
 	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
+// shutdownTimeout is how long running requests can take to finish after SIGTERM
+const shutdownTimeout = 20 * time.Second
+
 type ErrorResponse struct {
 	InsertId string
 }
 
 func executePlan(w http.ResponseWriter, r *http.Request, ctx context.Context, plan execute.Plan) {
+	bodyBytes := readBody(ctx, r)
 	ch := make(chan *treactorpb.Bond, plan.Calls())
 	plan.Execute(ctx, ch)
 
@@ -33,8 +46,10 @@ func executePlan(w http.ResponseWriter, r *http.Request, ctx context.Context, pl
 		Version:   resource.AppVersion,
 		Framework: resource.Framework,
 		Request: &treactorpb.TReactorRequest{
-			Path:    r.RequestURI,
-			Headers: make(map[string]string, len(r.Header)),
+			Path:      r.RequestURI,
+			Headers:   make(map[string]string, len(r.Header)),
+			Method:    r.Method,
+			BodyBytes: bodyBytes,
 		},
 		Bonds: make([]*treactorpb.Bond, elems),
 		Atom:  nil,
@@ -55,48 +70,75 @@ func failure(ctx context.Context, w http.ResponseWriter, r *http.Request, messag
 	errorResponse := &ErrorResponse{
 		InsertId: insertId,
 	}
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(400)
+	bytes, _ := json.MarshalIndent(errorResponse, "", "\t")
+	w.Write(bytes)
+}
+
+// injectedFailure answers with a 500, it's the outcome of an action like fail and not a bug
+func injectedFailure(ctx context.Context, w http.ResponseWriter, r *http.Request, message string) {
+	trace.SpanFromContext(ctx).SetStatus(codes.Error, message)
+	insertId := resource.Logger.Error(ctx, r, message)
+	errorResponse := &ErrorResponse{
+		InsertId: insertId,
+	}
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
 	bytes, _ := json.MarshalIndent(errorResponse, "", "\t")
 	w.Write(bytes)
 }
 
 func TReactSplitHandle(w http.ResponseWriter, r *http.Request) {
-	ctx, span := resource.Tracer.Start(r.Context(), "TReactSplitHandle", trace.WithAttributes(
-		attribute.String("x", "foo")))
-	defer span.End()
-	//_, span := trace.StartSpan(r.Context(), "split.Get")
-	//defer span.End()
-	//span.Annotate([]trace.Attribute{trace.StringAttribute("key", "value")}, "something happened")
-	//span.AddAttributes(trace.StringAttribute("hello", "world"))
 	url := r.URL
 	molecule := url.Query().Get("molecule")
+	ctx, span := resource.StartSpan(r.Context(), resource.GranularityHop, "TReactSplitHandle", trace.WithAttributes(
+		resource.MoleculeKey.String(molecule),
+		resource.BondDepthKey.Int(0)))
+	defer span.End()
 	resource.Logger.InfoF(ctx, "Starting reaction for molecule %s", molecule)
+	w.Header().Set("X-Treactor-Trace", trace.SpanFromContext(ctx).SpanContext().TraceID.String())
+	seed := execute.SeedFromRequest(r)
+	span.SetAttributes(resource.SeedKey.Int64(seed))
+	w.Header().Set(execute.SeedHeader, strconv.FormatInt(seed, 10))
 
 	plan, err := execute.Parse(molecule)
 	if err != nil {
 		failure(ctx, w, r, "Unable to parse molecule", err)
 		return
 	}
+	span.SetAttributes(resource.PlanKey.String(plan.String()))
 
-	executePlan(w, r, ctx, plan)
+	executePlan(w, r, execute.WithSeed(ctx, seed), plan)
 	resource.Logger.WarningF(ctx, "Cooling down reaction, finished %s", molecule)
 }
 
 func TReactBondHandle(w http.ResponseWriter, r *http.Request) {
-	ctx, span := resource.Tracer.Start(r.Context(), "TReactBondHandle")
-	defer span.End()
 	url := r.URL
-	plan, err := execute.Parse(url.Query().Get("molecule"))
+	molecule := url.Query().Get("molecule")
+	depth := execute.DepthFromRequest(r)
+	ctx, span := resource.StartSpan(r.Context(), resource.GranularityHop, "TReactBondHandle", trace.WithAttributes(
+		resource.MoleculeKey.String(molecule),
+		resource.BondDepthKey.Int(depth)))
+	defer span.End()
+	plan, err := execute.Parse(molecule)
 	if err != nil {
 		failure(r.Context(), w, r, "Unable to parse molecule", err)
 		return
 	}
+	span.SetAttributes(resource.PlanKey.String(plan.String()))
+	seed, path := execute.SeedFromRequest(r), execute.PathFromRequest(r)
+	span.SetAttributes(resource.SeedKey.Int64(seed), resource.HopPathKey.String(path))
+	ctx = execute.WithPath(execute.WithSeed(execute.WithDepth(ctx, depth), seed), path)
+	if degrade(ctx) {
+		injectedFailure(ctx, w, r, fmt.Sprintf("Bond %s degraded (errorRate:%g)", molecule, resource.CurrentSettings().ErrorRate))
+		return
+	}
 	executePlan(w, r, ctx, plan)
 }
 
 func TReactAtomHandle(w http.ResponseWriter, r *http.Request) {
-	ctx, span := resource.Tracer.Start(r.Context(), "TReactAtomHandle")
+	ctx, span := resource.StartSpan(r.Context(), resource.GranularityHop, "TReactAtomHandle")
 	defer span.End()
 
 	resource.Int64ValueRecorder.Measurement(12)
@@ -110,19 +152,94 @@ func TReactAtomHandle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	atom := resource.Atoms.ElementByName[strings.ToLower(block.Block)]
+	span.SetAttributes(resource.AtomAttributes(atom)...)
+	span.SetAttributes(resource.BondDepthKey.Int(execute.DepthFromRequest(r)))
+	span.SetAttributes(resource.KVAttributes(block.KV)...)
+	seed, path := execute.SeedFromRequest(r), execute.PathFromRequest(r)
+	span.SetAttributes(resource.SeedKey.Int64(seed), resource.HopPathKey.String(path))
+	ctx = execute.WithPath(execute.WithSeed(ctx, seed), path)
+
+	bodyBytes := readBody(ctx, r)
+	w = newFaultWriter(ctx, w, block.KV)
+	if block.KV["redirect"] != "" && redirect(ctx, w, r, block.KV["redirect"]) {
+		return
+	}
+
+	if degrade(ctx) {
+		injectedFailure(ctx, w, r, fmt.Sprintf("Atom %s degraded (errorRate:%g)", atom.Name, resource.CurrentSettings().ErrorRate))
+		return
+	}
 
-	var mb []byte
+	if resource.ElementBehavior && behave(ctx, atom) {
+		injectedFailure(ctx, w, r, fmt.Sprintf("Atom %s decayed", atom.Name))
+		return
+	}
+
+	// the resources the mem and stress actions keep till the atom answered
+	var release []func()
+	defer func() {
+		for _, r := range release {
+			r()
+		}
+	}()
 	if block.KV["mem"] != "" {
-		mb = mem(ctx, block.KV["mem"])
+		release = append(release, mem(ctx, block.KV["mem"]))
+	}
+	if block.KV["disk"] != "" {
+		release = append(release, disk(ctx, block.KV["disk"]))
+	}
+	if block.KV["goroutines"] != "" {
+		release = append(release, goroutines(ctx, block.KV["goroutines"]))
+	}
+	if block.KV["fd"] != "" {
+		release = append(release, fd(ctx, block.KV["fd"]))
+	}
+	if block.KV["lock"] != "" {
+		lock(ctx, block.KV["lock"])
 	}
 
 	if block.KV["cpu"] != "" {
 		cpu(ctx, block.KV["cpu"])
 	}
 
-	span.AddEvent("AtomEvent",
-		// TODO: label.Int("foo", 12)
-	)
+	var computed *treactorpb.Pi
+	if block.KV["pi"] != "" {
+		computed = computePi(ctx, block.KV["pi"])
+	}
+
+	if block.KV["sleep"] != "" {
+		sleep(ctx, block.KV["sleep"])
+	}
+
+	if block.KV["spans"] != "" {
+		spans(ctx, block.KV["spans"])
+	}
+
+	if block.KV["log"] != "" {
+		resource.EmitLogs(ctx, block.KV)
+	}
+
+	if block.KV["panic"] != "" {
+		panicking(ctx, atom.Name, block.KV["panic"])
+	}
+
+	if block.KV["crash"] != "" {
+		crash(ctx, atom.Name, block.KV["crash"])
+	}
+
+	if block.KV["hang"] != "" && hang(ctx, block.KV["hang"]) {
+		return
+	}
+
+	if block.KV["fail"] != "" && fail(ctx, block.KV["fail"]) {
+		injectedFailure(ctx, w, r, fmt.Sprintf("Atom %s failed (fail:%s)", atom.Name, block.KV["fail"]))
+		return
+	}
+
+	var padding string
+	if block.KV["payload"] != "" {
+		padding = payload(ctx, block.KV["payload"])
+	}
 
 	resource.Logger.InfoF(r.Context(), "Atom %s (%d)", atom.Name, atom.Number)
 
@@ -131,17 +248,15 @@ func TReactAtomHandle(w http.ResponseWriter, r *http.Request) {
 		Version:   resource.AppVersion,
 		Framework: resource.Framework,
 		Request: &treactorpb.TReactorRequest{
-			Path:    r.RequestURI,
-			Headers: make(map[string]string, len(r.Header)),
-		},
-		Bonds: nil,
-		Atom: &treactorpb.Atom{
-			Number: resource.Number,
-			Symbol: atom.Symbol,
-			Name:   atom.Name,
-			Period: &atom.Period,
-			Group:  &atom.Group,
+			Path:      r.RequestURI,
+			Headers:   make(map[string]string, len(r.Header)),
+			Method:    r.Method,
+			BodyBytes: bodyBytes,
 		},
+		Bonds:   nil,
+		Atom:    atomProto(atom),
+		Pi:      computed,
+		Payload: padding,
 	}
 	for key, values := range r.Header {
 		node.Request.Headers[key] = strings.Join(values, "|")
@@ -150,11 +265,10 @@ func TReactAtomHandle(w http.ResponseWriter, r *http.Request) {
 	bytes, _ := protojson.Marshal(node)
 	w.Header().Set("Content-Type", "application/json")
 	w.Write(bytes)
-	_ = mb
 }
 
 func TReactInfoHandle(w http.ResponseWriter, r *http.Request) {
-	_, span := resource.Tracer.Start(r.Context(), "TReactInfoHandle")
+	_, span := resource.StartSpan(r.Context(), resource.GranularityHop, "TReactInfoHandle")
 	defer span.End()
 
 	atom := resource.Atoms.ElementByNumber[resource.Number]
@@ -166,15 +280,11 @@ func TReactInfoHandle(w http.ResponseWriter, r *http.Request) {
 		Request: &treactorpb.TReactorRequest{
 			Path:    r.RequestURI,
 			Headers: make(map[string]string, len(r.Header)),
+			Method:  r.Method,
 		},
-		Bonds: nil,
-		Atom: &treactorpb.Atom{
-			Number: resource.Number,
-			Symbol: atom.Symbol,
-			Name:   atom.Name,
-			Period: &atom.Period,
-			Group:  &atom.Group,
-		},
+		Bonds:    nil,
+		Atom:     atomProto(atom),
+		Settings: settingsProto(resource.CurrentSettings()),
 	}
 	for key, values := range r.Header {
 		node.Request.Headers[key] = strings.Join(values, "|")
@@ -186,7 +296,7 @@ func TReactInfoHandle(w http.ResponseWriter, r *http.Request) {
 }
 
 func TReactReactionsHandle(w http.ResponseWriter, r *http.Request) {
-	_, span := resource.Tracer.Start(r.Context(), "TReactReactionsHandle")
+	_, span := resource.StartSpan(r.Context(), resource.GranularityHop, "TReactReactionsHandle")
 	defer span.End()
 
 	node := &treactorpb.Node{
@@ -196,6 +306,7 @@ func TReactReactionsHandle(w http.ResponseWriter, r *http.Request) {
 		Request: &treactorpb.TReactorRequest{
 			Path:    r.RequestURI,
 			Headers: make(map[string]string, len(r.Header)),
+			Method:  r.Method,
 		},
 	}
 	for key, values := range r.Header {
@@ -257,34 +368,117 @@ func TReactReactionsHandle(w http.ResponseWriter, r *http.Request) {
 	w.Write(bytes)
 }
 
-func TReactorHealthz(_ http.ResponseWriter, _ *http.Request) {
+// You can have a catch all tracer on the route, but it's better to instrument the handlers separate. The span is
+// named after the method, a call with a body is a POST.
+func instrumented(mux *http.ServeMux, route string, handleFunction func(w http.ResponseWriter, r *http.Request)) {
+	fullRoute := fmt.Sprintf("%s%s", resource.Base, route)
+	mux.Handle(fullRoute, otelhttp.NewHandler(recovery(http.HandlerFunc(handleFunction)), fullRoute,
+		otelhttp.WithSpanNameFormatter(func(route string, r *http.Request) string {
+			return fmt.Sprintf("%s %s", r.Method, route)
+		})))
 }
 
-// You can have a catch all tracer on the route, but it's better to instrument the handlers separate
-func instrumentedGet(mux *http.ServeMux, route string, handleFunction func(w http.ResponseWriter, r *http.Request)) {
-	fullRoute := fmt.Sprintf("%s%s", resource.Base, route)
-	mux.Handle(fullRoute, otelhttp.NewHandler(http.HandlerFunc(handleFunction), fmt.Sprintf("GET %s", fullRoute)))
+// recovery turns a panic of the handler into a 500. The panic is recorded as an exception event on the server span
+// and logged with the stack.
+func recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			ctx := r.Context()
+			err := fmt.Errorf("panic: %v", p)
+			span := trace.SpanFromContext(ctx)
+			span.RecordError(err, trace.WithAttributes(
+				attribute.String("exception.type", "panic"),
+				attribute.String("exception.stacktrace", string(debug.Stack()))))
+			span.SetStatus(codes.Error, err.Error())
+			insertId := resource.Logger.ErrorErr(ctx, r, "Recovered from panic", err)
+			errorResponse := &ErrorResponse{
+				InsertId: insertId,
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(500)
+			bytes, _ := json.MarshalIndent(errorResponse, "", "\t")
+			w.Write(bytes)
+		}()
+		next.ServeHTTP(w, r)
+	})
 }
 
 func Serve() {
-	atoms := element.NewAtoms()
-
 	fmt.Printf("Telemetry Reactor (%s:%s) listening on port %s\n", resource.AppName, resource.AppVersion, resource.Port)
 	fmt.Printf("Mode: %s\n", resource.Mode)
+	if resource.TraceStore != nil {
+		fmt.Printf("Traces: http://localhost:%s%s/traces\n", resource.Port, resource.Base)
+	}
 
+	startProbes()
 	r := http.NewServeMux()
-	r.HandleFunc("/healthz", TReactorHealthz)
-	instrumentedGet(r, fmt.Sprintf("/nodes/%d/health", resource.Number), TReactorHealthz)
-	instrumentedGet(r, fmt.Sprintf("/nodes/%d/info", resource.Number), TReactInfoHandle)
-	instrumentedGet(r, "/reactions", TReactSplitHandle)
+	r.HandleFunc("/healthz", TReactorLivez)
+	r.HandleFunc("/livez", TReactorLivez)
+	r.HandleFunc("/readyz", TReactorReadyz)
+	r.HandleFunc("/startupz", TReactorStartupz)
+	r.HandleFunc(fmt.Sprintf("%s/traces", resource.Base), TReactTracesHandle)
+	r.HandleFunc(fmt.Sprintf("%s/traces/", resource.Base), TReactTracesHandle)
+	instrumented(r, fmt.Sprintf("/nodes/%d/health", resource.Number), TReactorLivez)
+	instrumented(r, fmt.Sprintf("/nodes/%d/ready", resource.Number), TReactorReadyz)
+	instrumented(r, fmt.Sprintf("/nodes/%d/startup", resource.Number), TReactorStartupz)
+	instrumented(r, fmt.Sprintf("/nodes/%d/info", resource.Number), TReactInfoHandle)
+	instrumented(r, "/reactions", counted(TReactSplitHandle))
+	instrumented(r, "/elements", TReactElementsHandle)
 	for i := 1; i <= resource.MaxBond; i++ {
-		instrumentedGet(r, fmt.Sprintf("/bonds/%d", i), TReactBondHandle)
+		instrumented(r, fmt.Sprintf("/bonds/%d", i), counted(TReactBondHandle))
+	}
+	instrumented(r, "/bonds/n", counted(TReactBondHandle))
+	for sym := range resource.Atoms.ElementByName {
+		instrumented(r, fmt.Sprintf("/atoms/%s", strings.ToLower(sym)), counted(TReactAtomHandle))
 	}
-	instrumentedGet(r, "/bonds/n", TReactBondHandle)
-	for sym := range atoms.ElementByName {
-		instrumentedGet(r, fmt.Sprintf("/atoms/%s", strings.ToLower(sym)), TReactAtomHandle)
+	if resource.IsCollectorMode() {
+		serveCollector(r)
 	}
+	if resource.IsProfiling() {
+		runtime.SetBlockProfileRate(int(time.Millisecond / 10))
+		runtime.SetMutexProfileFraction(10)
+		r.HandleFunc("/debug/pprof/", pprof.Index)
+		r.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		r.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		r.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		r.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	serveAdmin()
 	http.Handle("/", r)
 
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", resource.Port), r))
+	server := &http.Server{Addr: fmt.Sprintf(":%s", resource.Port), Handler: recovery(r)}
+	stopped := make(chan struct{})
+	go shutdownOnSignal(server, stopped)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	<-stopped
+	resource.Shutdown(context.Background())
+}
+
+// shutdownOnSignal fails the readiness on SIGTERM or SIGINT, after the drain delay it stops accepting requests and
+// waits for the running ones
+func shutdownOnSignal(server *http.Server, stopped chan<- struct{}) {
+	defer close(stopped)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	<-signals
+	// the readiness fails first, so the endpoint is removed before the server stops accepting requests
+	atomic.StoreInt32(&draining, 1)
+	if resource.DrainDelay > 0 {
+		log.Printf("draining for %s", resource.DrainDelay)
+		time.Sleep(resource.DrainDelay)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("failed to shutdown: %v", err)
+	}
 }
@@ -0,0 +1,15 @@
+package treact
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/treactor/treactor-go/pkg/resource"
+)
+
+// TestMain discards the logs of the actions for all the tests of the package
+func TestMain(m *testing.M) {
+	resource.Logger = resource.NewLogger("text", ioutil.Discard)
+	os.Exit(m.Run())
+}
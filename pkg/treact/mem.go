@@ -0,0 +1,158 @@
+package treact
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// pageSize is the stride used to touch the allocations, every page is written so it counts in the RSS
+const pageSize = 4096
+
+// defaultLeakCap is where a leak stops growing when mem:N@leak doesn't set a cap
+const defaultLeakCap = 1024 * 1024 * 1024
+
+// churnChunk is the size of the short lived allocations of the churn mode
+const churnChunk = 64 * 1024
+
+// memSpec is the mem KV annotation:
+//
+//	mem:N                 allocate N megabytes, kept till the atom answers
+//	mem:N@hold:D          allocate N megabytes and keep them for D after the atom answered (like 30s)
+//	mem:N@leak[:C]        leak N megabytes per request till the leak reaches C (default 1g)
+//	mem:N@churn:D         allocate and drop N megabytes in small chunks for D, to put the GC under pressure
+type memSpec struct {
+	Size     int
+	Mode     string
+	Duration time.Duration
+	Cap      int
+}
+
+var leak = struct {
+	sync.Mutex
+	blocks [][]byte
+	size   int
+}{}
+
+func parseMemory(value string) (*memSpec, error) {
+	spec := strings.SplitN(value, "@", 2)
+	megabytes, err := strconv.ParseFloat(spec[0], 64)
+	if err != nil || megabytes < 0 || megabytes > math.MaxInt32 {
+		return nil, fmt.Errorf("mem needs a number of megabytes")
+	}
+	memory := &memSpec{Size: int(megabytes * 1024 * 1024), Mode: "request"}
+	if len(spec) == 1 {
+		return memory, nil
+	}
+	option := strings.SplitN(spec[1], ":", 2)
+	memory.Mode = option[0]
+	switch memory.Mode {
+	case "hold", "churn":
+		if len(option) != 2 {
+			return nil, fmt.Errorf("mem %s needs a duration, like mem:64@%s:10s", memory.Mode, memory.Mode)
+		}
+		memory.Duration, err = resource.ParseDuration(option[1])
+		if err != nil {
+			return nil, err
+		}
+	case "leak":
+		memory.Cap = defaultLeakCap
+		if len(option) == 2 {
+			memory.Cap, err = resource.ParseSize(option[1])
+			if err != nil {
+				return nil, fmt.Errorf("mem leak needs a cap, like mem:%s@leak:512m", spec[0])
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown mem mode %s", memory.Mode)
+	}
+	return memory, nil
+}
+
+// touch writes every page of the allocation, untouched pages are not backed by memory
+func touch(b []byte) []byte {
+	for i := 0; i < len(b); i += pageSize {
+		b[i] = 1
+	}
+	return b
+}
+
+// mem runs the memory action, the returned release is run when the atom answered. It keeps the allocation alive
+// till then, for the hold mode it starts the hold.
+func mem(ctx context.Context, value string) func() {
+	memory, err := parseMemory(value)
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Ignoring mem action: %s", err)
+		return func() {}
+	}
+	release := func() {}
+	attributes := []attribute.KeyValue{
+		attribute.Int64("treactor.mem.bytes", int64(memory.Size)),
+		attribute.String("treactor.mem.mode", memory.Mode),
+	}
+	switch memory.Mode {
+	case "request":
+		allocation := touch(make([]byte, memory.Size))
+		release = func() {
+			runtime.KeepAlive(allocation)
+		}
+	case "hold":
+		held := touch(make([]byte, memory.Size))
+		release = func() {
+			time.AfterFunc(memory.Duration, func() {
+				runtime.KeepAlive(held)
+			})
+		}
+	case "leak":
+		leak.Lock()
+		if leak.size+memory.Size <= memory.Cap {
+			leak.blocks = append(leak.blocks, touch(make([]byte, memory.Size)))
+			leak.size += memory.Size
+		}
+		attributes = append(attributes, attribute.Int64("treactor.mem.leaked_bytes", int64(leak.size)))
+		leak.Unlock()
+	case "churn":
+		chunks := churn(ctx, memory)
+		attributes = append(attributes, attribute.Int64("treactor.mem.churned_bytes", int64(chunks)*churnChunk))
+	}
+	actionEvent(ctx, "mem", value, attributes...)
+	heapStats(ctx)
+	return release
+}
+
+// churn allocates the size in short lived chunks over and over for the duration, it returns the number of chunks
+func churn(ctx context.Context, memory *memSpec) int {
+	deadline := time.Now().Add(memory.Duration)
+	live := make([][]byte, (memory.Size+churnChunk-1)/churnChunk)
+	chunks := 0
+	for time.Now().Before(deadline) && ctx.Err() == nil {
+		for i := range live {
+			live[i] = touch(make([]byte, churnChunk))
+			chunks++
+		}
+	}
+	runtime.KeepAlive(live)
+	return chunks
+}
+
+// heapStats puts the heap and gc statistics on the span of the atom
+func heapStats(ctx context.Context) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int64("treactor.mem.heap_alloc", int64(stats.HeapAlloc)),
+		attribute.Int64("treactor.mem.heap_sys", int64(stats.HeapSys)),
+		attribute.Int64("treactor.mem.heap_objects", int64(stats.HeapObjects)),
+		attribute.Int64("treactor.mem.sys", int64(stats.Sys)),
+		attribute.Int64("treactor.mem.gc_count", int64(stats.NumGC)),
+		attribute.Int64("treactor.mem.gc_pause_total_ns", int64(stats.PauseTotalNs)))
+}
@@ -0,0 +1,40 @@
+package treact
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMemory(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		expected memSpec
+	}{
+		{"64", memSpec{Size: 64 << 20, Mode: "request"}},
+		{"0.5", memSpec{Size: 512 << 10, Mode: "request"}},
+		{"64@hold:10s", memSpec{Size: 64 << 20, Mode: "hold", Duration: 10 * time.Second}},
+		{"16@leak", memSpec{Size: 16 << 20, Mode: "leak", Cap: 1 << 30}},
+		{"16@leak:256m", memSpec{Size: 16 << 20, Mode: "leak", Cap: 256 << 20}},
+		{"8@churn:500", memSpec{Size: 8 << 20, Mode: "churn", Duration: 500 * time.Millisecond}},
+	} {
+		memory, err := parseMemory(test.in)
+		assert.NoError(t, err, test.in)
+		assert.Equal(t, test.expected, *memory, test.in)
+	}
+
+	for _, in := range []string{"x", "-1", "64@hold", "64@churn:soon", "64@grow", "16@leak:"} {
+		_, err := parseMemory(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestMemLeakCap(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		mem(context.Background(), "1@leak:3m")
+	}
+	assert.Equal(t, 3<<20, leak.size)
+	assert.Len(t, leak.blocks, 3)
+}
@@ -0,0 +1,42 @@
+package treact
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/treactor/treactor-go/pkg/execute"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// payload generates the padding of the node of the atom:
+//
+//	payload:N             N bytes of random characters, they don't compress (k and m suffixes allowed)
+//	payload:N@text        N bytes of repeated text, they compress well
+func payload(ctx context.Context, value string) string {
+	spec, err := execute.ParseBlob("payload", value)
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Ignoring payload action: %s", err)
+		return ""
+	}
+	actionEvent(ctx, "payload", value,
+		attribute.Int("treactor.payload.bytes", spec.Size),
+		attribute.String("treactor.payload.mode", spec.Mode))
+	return string(spec.Generate(ctx, "payload"))
+}
+
+// readBody reads the body the caller posted and returns its size, the body itself isn't used
+func readBody(ctx context.Context, r *http.Request) int64 {
+	if r.Body == nil || r.Body == http.NoBody {
+		return 0
+	}
+	n, err := io.Copy(ioutil.Discard, r.Body)
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Reading the request body failed after %d bytes: %s", n, err)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("treactor.request.body_bytes", n))
+	return n
+}
@@ -0,0 +1,19 @@
+package treact
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayload(t *testing.T) {
+	assert.Len(t, payload(context.Background(), "64k"), 64*1024)
+	assert.Equal(t, "", payload(context.Background(), "64k@zip"))
+
+	r := httptest.NewRequest("POST", "/treact/atoms/h?symbol=H", strings.NewReader(strings.Repeat("x", 1000)))
+	assert.Equal(t, int64(1000), readBody(context.Background(), r))
+	assert.Equal(t, int64(0), readBody(context.Background(), httptest.NewRequest("GET", "/treact/atoms/h", nil)))
+}
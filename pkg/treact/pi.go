@@ -0,0 +1,112 @@
+package treact
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	treactorpb "github.com/treactor/treactor-go/io/treactor/v1alpha"
+	"github.com/treactor/treactor-go/pkg/pi"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	maxPiTerms = 1000 * 1000 * 1000
+	// maxParallelPiTerms limits the goroutines of the parallel strategy, every one of them lives till the sum
+	maxParallelPiTerms = 100 * 1000
+)
+
+// piSpec is the pi KV annotation:
+//
+//	pi:N                  compute pi with N terms of the Leibniz series (k, m and g suffixes allowed, powers of 1000)
+//	pi:N@single           in the goroutine of the atom (default)
+//	pi:N@parallel         a goroutine per term, at most 100k terms, it isn't stopped by a cancelled request
+//	pi:N@chunked[:W]      a pool of W workers (default GOMAXPROCS) summing chunks of terms
+type piSpec struct {
+	Terms    int
+	Strategy string
+	Workers  int
+}
+
+func parsePi(value string) (*piSpec, error) {
+	spec := strings.SplitN(value, "@", 2)
+	terms, err := resource.ParseQuantity(spec[0], 1000)
+	if err != nil || terms < 1 || terms > maxPiTerms {
+		return nil, fmt.Errorf("pi needs a number of terms between 1 and %d", maxPiTerms)
+	}
+	p := &piSpec{Terms: terms, Strategy: "single", Workers: 1}
+	if len(spec) == 1 {
+		return p, nil
+	}
+	option := strings.SplitN(spec[1], ":", 2)
+	p.Strategy = option[0]
+	switch p.Strategy {
+	case "single":
+	case "parallel":
+		if terms > maxParallelPiTerms {
+			return nil, fmt.Errorf("pi parallel starts a goroutine per term, it needs at most %d terms", maxParallelPiTerms)
+		}
+		p.Workers = terms
+	case "chunked":
+		p.Workers = runtime.GOMAXPROCS(0)
+		if len(option) == 2 {
+			p.Workers, err = strconv.Atoi(option[1])
+			if err != nil || p.Workers < 1 || p.Workers > maxCores {
+				return nil, fmt.Errorf("pi chunked needs a number of workers between 1 and %d", maxCores)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown pi strategy %s", p.Strategy)
+	}
+	return p, nil
+}
+
+// computePi runs the pi action, the result goes in the node of the atom
+func computePi(ctx context.Context, value string) *treactorpb.Pi {
+	p, err := parsePi(value)
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Ignoring pi action: %s", err)
+		return nil
+	}
+	// the series sums the terms 0 to n, single and chunked stop early when the request is cancelled
+	n := p.Terms - 1
+	var result float64
+	terms := p.Terms
+	start := time.Now()
+	pprof.Do(ctx, pprof.Labels("treactor.action", "pi"), func(ctx context.Context) {
+		switch p.Strategy {
+		case "single":
+			result, terms = pi.Series(ctx, n)
+		case "parallel":
+			result = pi.Parallel(n)
+		case "chunked":
+			result, terms = pi.Chunked(ctx, n, p.Workers)
+		}
+	})
+	duration := time.Now().Sub(start)
+	if terms < p.Terms {
+		resource.Logger.WarningF(ctx, "Pi action cancelled after %d of %d terms", terms, p.Terms)
+	}
+	computed := &treactorpb.Pi{
+		Strategy:   p.Strategy,
+		Terms:      int64(terms),
+		Value:      result,
+		Error:      math.Abs(result - math.Pi),
+		Goroutines: int32(p.Workers),
+		DurationMs: duration.Milliseconds(),
+	}
+	actionEvent(ctx, "pi", value,
+		attribute.String("treactor.pi.strategy", computed.Strategy),
+		attribute.Int64("treactor.pi.terms", computed.Terms),
+		attribute.Float64("treactor.pi.value", computed.Value),
+		attribute.Float64("treactor.pi.error", computed.Error),
+		attribute.Int("treactor.pi.goroutines", p.Workers),
+		attribute.Int64("treactor.pi.duration_ms", computed.DurationMs))
+	return computed
+}
@@ -0,0 +1,50 @@
+package treact
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePi(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		expected piSpec
+	}{
+		{"1000", piSpec{Terms: 1000, Strategy: "single", Workers: 1}},
+		{"10k@parallel", piSpec{Terms: 10000, Strategy: "parallel", Workers: 10000}},
+		{"5m@chunked", piSpec{Terms: 5000000, Strategy: "chunked", Workers: runtime.GOMAXPROCS(0)}},
+		{"1.5m@chunked:4", piSpec{Terms: 1500000, Strategy: "chunked", Workers: 4}},
+	} {
+		p, err := parsePi(test.in)
+		assert.NoError(t, err, test.in)
+		assert.Equal(t, test.expected, *p, test.in)
+	}
+
+	for _, in := range []string{"x", "0", "1m@parallel", "1m@chunked:0", "1m@gpu", "@single"} {
+		_, err := parsePi(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestComputePi(t *testing.T) {
+	for _, value := range []string{"100k", "10k@parallel", "100k@chunked:3"} {
+		computed := computePi(context.Background(), value)
+		assert.InDelta(t, 3.14159, computed.Value, 0.0001, value)
+		assert.Less(t, computed.Error, 0.0001, value)
+	}
+}
+
+func TestComputePiCancelled(t *testing.T) {
+	for _, value := range []string{"1g", "1g@chunked:2"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		start := time.Now()
+		computed := computePi(ctx, value)
+		cancel()
+		assert.Less(t, time.Since(start).Milliseconds(), int64(30*1000), value)
+		assert.Less(t, computed.Terms, int64(1000*1000*1000), value)
+	}
+}
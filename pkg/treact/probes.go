@@ -0,0 +1,168 @@
+package treact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/treactor/treactor-go/pkg/resource"
+)
+
+const (
+	// dependencyTimeout is how long the readiness probe waits for a dependency
+	dependencyTimeout = 2 * time.Second
+	// ProbeHeader marks the calls of the readiness probe, they aren't counted as served
+	ProbeHeader = "X-Treactor-Probe"
+)
+
+var (
+	startedAt time.Time
+	// draining is set on SIGTERM, the readiness probe fails while the running requests finish
+	draining int32
+	// served counts the reactions, bonds and atoms served, for TREACTOR_UNREADY_AFTER
+	served int64
+	// flapUp and flapDown are the periods the liveness probe succeeds and fails, from TREACTOR_LIVENESS_FLAP
+	flapUp, flapDown time.Duration
+)
+
+type ProbeResponse struct {
+	Probe   string
+	Status  string
+	Reasons []string `json:",omitempty"`
+}
+
+// startProbes starts the clock of the startup delay and the liveness flapping
+func startProbes() {
+	startedAt = time.Now()
+	if resource.LivenessFlap == "" {
+		return
+	}
+	var err error
+	spec := strings.SplitN(resource.LivenessFlap, "/", 2)
+	if len(spec) != 2 {
+		err = fmt.Errorf("flap needs the up and down periods, like 50s/10s")
+	} else if flapUp, err = resource.ParseDuration(spec[0]); err == nil {
+		flapDown, err = resource.ParseDuration(spec[1])
+	}
+	if err != nil || flapUp <= 0 || flapDown <= 0 {
+		resource.Logger.WarningF(context.Background(), "Ignoring TREACTOR_LIVENESS_FLAP %s: %v", resource.LivenessFlap, err)
+		flapUp, flapDown = 0, 0
+	}
+}
+
+// counted counts the requests of the handler, the readiness fails after TREACTOR_UNREADY_AFTER of them
+func counted(handleFunction func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(ProbeHeader) == "" {
+			atomic.AddInt64(&served, 1)
+		}
+		handleFunction(w, r)
+	}
+}
+
+// startup fails till the startup delay passed
+func startup(context.Context) []string {
+	if elapsed := time.Now().Sub(startedAt); elapsed < resource.StartupDelay {
+		return []string{fmt.Sprintf("starting, %s of %s", elapsed.Truncate(time.Millisecond), resource.StartupDelay)}
+	}
+	return nil
+}
+
+// liveness fails in the down period of the flapping
+func liveness(context.Context) []string {
+	if flapUp <= 0 {
+		return nil
+	}
+	if phase := time.Now().Sub(startedAt) % (flapUp + flapDown); phase >= flapUp {
+		return []string{fmt.Sprintf("flapping, down for %s of %s", (phase - flapUp).Truncate(time.Millisecond), flapDown)}
+	}
+	return nil
+}
+
+// readiness fails while starting or draining, after TREACTOR_UNREADY_AFTER requests, when the admin API made the
+// instance not ready or when one of the dependencies fails
+func readiness(ctx context.Context) []string {
+	reasons := startup(ctx)
+	if atomic.LoadInt32(&draining) == 1 {
+		reasons = append(reasons, "draining")
+	}
+	if n := atomic.LoadInt64(&served); resource.UnreadyAfter > 0 && n >= resource.UnreadyAfter {
+		reasons = append(reasons, fmt.Sprintf("served %d of %d requests", n, resource.UnreadyAfter))
+	}
+	if !resource.CurrentSettings().Ready {
+		reasons = append(reasons, "not ready by the admin API")
+	}
+	return append(reasons, dependencies(ctx)...)
+}
+
+// dependencies calls the atoms of TREACTOR_READY_DEPENDENCIES, the ones that don't answer or fail are returned
+func dependencies(ctx context.Context) []string {
+	failed := make([]string, len(resource.ReadyDependencies))
+	wg := sync.WaitGroup{}
+	wg.Add(len(resource.ReadyDependencies))
+	for i, symbol := range resource.ReadyDependencies {
+		i, symbol := i, symbol
+		go func() {
+			defer wg.Done()
+			if err := dependency(ctx, symbol); err != nil {
+				failed[i] = fmt.Sprintf("dependency %s: %s", symbol, err)
+			}
+		}()
+	}
+	wg.Wait()
+	var reasons []string
+	for _, reason := range failed {
+		if reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons
+}
+
+func dependency(ctx context.Context, symbol string) error {
+	ctx, cancel := context.WithTimeout(ctx, dependencyTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", resource.AtomUrl(symbol), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(ProbeHeader, "readiness")
+	response, err := resource.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, response.Body)
+	if response.StatusCode >= 400 {
+		return errors.New(response.Status)
+	}
+	return nil
+}
+
+// probe answers 200 when the check has no reasons to fail, 503 with the reasons otherwise
+func probe(name string, check func(ctx context.Context) []string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := &ProbeResponse{Probe: name, Status: "ok", Reasons: check(r.Context())}
+		w.Header().Set("Content-Type", "application/json")
+		if len(response.Reasons) > 0 {
+			response.Status = "failing"
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		bytes, _ := json.MarshalIndent(response, "", "\t")
+		w.Write(bytes)
+	}
+}
+
+var (
+	TReactorLivez    = probe("liveness", liveness)
+	TReactorReadyz   = probe("readiness", readiness)
+	TReactorStartupz = probe("startup", startup)
+)
@@ -0,0 +1,89 @@
+package treact
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/treactor/treactor-go/pkg/resource"
+)
+
+// probeCode runs the probe handler and returns the status code
+func probeCode(handler func(w http.ResponseWriter, r *http.Request)) int {
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/", nil))
+	return w.Code
+}
+
+func TestProbes(t *testing.T) {
+	ready, unready := true, false
+	defer func() {
+		resource.UpdateSettings(resource.SettingsUpdate{Ready: &ready})
+		resource.StartupDelay, resource.LivenessFlap, resource.UnreadyAfter = 0, "", 0
+		atomic.StoreInt32(&draining, 0)
+		atomic.StoreInt64(&served, 0)
+		startProbes()
+	}()
+
+	resource.StartupDelay = time.Hour
+	startProbes()
+	assert.Equal(t, 503, probeCode(TReactorStartupz))
+	assert.Equal(t, 503, probeCode(TReactorReadyz))
+	assert.Equal(t, 200, probeCode(TReactorLivez))
+	resource.StartupDelay = 0
+	assert.Equal(t, 200, probeCode(TReactorStartupz))
+	assert.Equal(t, 200, probeCode(TReactorReadyz))
+
+	resource.UnreadyAfter = 2
+	handler := counted(func(w http.ResponseWriter, r *http.Request) {})
+	handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, 200, probeCode(TReactorReadyz))
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(ProbeHeader, "readiness")
+	handler(httptest.NewRecorder(), r)
+	assert.Equal(t, 200, probeCode(TReactorReadyz))
+	handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, 503, probeCode(TReactorReadyz))
+	resource.UnreadyAfter = 0
+
+	atomic.StoreInt32(&draining, 1)
+	assert.Equal(t, 503, probeCode(TReactorReadyz))
+	atomic.StoreInt32(&draining, 0)
+
+	resource.UpdateSettings(resource.SettingsUpdate{Ready: &unready})
+	assert.Equal(t, []string{"not ready by the admin API"}, readiness(httptest.NewRequest("GET", "/", nil).Context()))
+	resource.UpdateSettings(resource.SettingsUpdate{Ready: &ready})
+	assert.Equal(t, 200, probeCode(TReactorReadyz))
+
+	resource.LivenessFlap = "1h/1h"
+	startProbes()
+	assert.Equal(t, 200, probeCode(TReactorLivez))
+	startedAt = startedAt.Add(-90 * time.Minute)
+	assert.Equal(t, 503, probeCode(TReactorLivez))
+	resource.LivenessFlap = "1h"
+	startProbes()
+	assert.Equal(t, 200, probeCode(TReactorLivez))
+}
+
+func TestDependencies(t *testing.T) {
+	atom := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("symbol") == "O" {
+			w.WriteHeader(500)
+		}
+	}))
+	defer atom.Close()
+	mode, port, client := resource.Mode, resource.Port, resource.HttpClient
+	resource.Mode, resource.Port = "local", atom.URL[len("http://127.0.0.1:"):]
+	resource.HttpClient = http.DefaultClient
+	defer func() {
+		resource.Mode, resource.Port, resource.HttpClient, resource.ReadyDependencies = mode, port, client, nil
+	}()
+
+	resource.ReadyDependencies = []string{"H"}
+	assert.Equal(t, 200, probeCode(TReactorReadyz))
+	resource.ReadyDependencies = []string{"H", "O"}
+	assert.Equal(t, []string{"dependency O: 500 Internal Server Error"}, readiness(httptest.NewRequest("GET", "/", nil).Context()))
+}
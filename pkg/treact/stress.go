@@ -0,0 +1,262 @@
+package treact
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/treactor/treactor-go/pkg/pi"
+	"github.com/treactor/treactor-go/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/unit"
+)
+
+const (
+	maxDiskMegabytes = 100 * 1024
+	maxGoroutines    = 1000 * 1000
+	maxFds           = 100 * 1000
+)
+
+// The metrics of the stress actions, the up down counters go down again when the action cleans up
+var (
+	meter          = metric.Must(global.Meter("io.treactor.tracing.golang"))
+	diskWritten    = meter.NewInt64Counter("treactor.disk.written", metric.WithUnit(unit.Bytes), metric.WithDescription("Bytes written by the disk action"))
+	diskKept       = meter.NewInt64UpDownCounter("treactor.disk.kept", metric.WithUnit(unit.Bytes), metric.WithDescription("Bytes on disk kept by the disk action"))
+	goroutinesHeld = meter.NewInt64UpDownCounter("treactor.goroutines.held", metric.WithDescription("Goroutines parked by the goroutines action"))
+	lockWait       = meter.NewInt64ValueRecorder("treactor.lock.wait", metric.WithUnit(unit.Milliseconds), metric.WithDescription("Time waited for the shared lock of the lock action"))
+	fdsHeld        = meter.NewInt64UpDownCounter("treactor.fd.held", metric.WithDescription("File descriptors held by the fd action"))
+)
+
+// retention is how long an action keeps what it took:
+//
+//	N                     till the atom answers
+//	N@hold:D              for D after the atom answered (like 30s)
+//	N@leak                never, every call adds to it
+type retention struct {
+	Mode     string
+	Duration time.Duration
+}
+
+// parseRetention parses the amount and the retention of the value of an action, like 16@hold:10s
+func parseRetention(action string, value string) (float64, retention, error) {
+	spec := strings.SplitN(value, "@", 2)
+	amount, err := strconv.ParseFloat(spec[0], 64)
+	if err != nil || amount < 0 {
+		return 0, retention{}, fmt.Errorf("%s needs a number", action)
+	}
+	r := retention{Mode: "request"}
+	if len(spec) == 1 {
+		return amount, r, nil
+	}
+	option := strings.SplitN(spec[1], ":", 2)
+	r.Mode = option[0]
+	switch r.Mode {
+	case "hold":
+		if len(option) != 2 {
+			return 0, r, fmt.Errorf("%s hold needs a duration, like %s:%s@hold:10s", action, action, spec[0])
+		}
+		r.Duration, err = resource.ParseDuration(option[1])
+		if err != nil {
+			return 0, r, err
+		}
+	case "leak":
+	default:
+		return 0, r, fmt.Errorf("unknown %s mode %s", action, r.Mode)
+	}
+	return amount, r, nil
+}
+
+// release returns what runs when the atom answered: the cleanup for the request mode, the start of the hold after
+// which the cleanup runs for the hold mode and nothing for the leak mode
+func (r retention) release(cleanup func()) func() {
+	switch r.Mode {
+	case "hold":
+		return func() {
+			time.AfterFunc(r.Duration, cleanup)
+		}
+	case "request":
+		return cleanup
+	}
+	return func() {}
+}
+
+// disk writes and fsyncs megabytes to a file in TREACTOR_DISK_DIR, the file is removed when the retention ends
+func disk(ctx context.Context, value string) func() {
+	megabytes, r, err := parseRetention("disk", value)
+	if err == nil && megabytes > maxDiskMegabytes {
+		err = fmt.Errorf("disk needs at most %d megabytes", maxDiskMegabytes)
+	}
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Ignoring disk action: %s", err)
+		return func() {}
+	}
+	start := time.Now()
+	written, name, err := writeFile(int64(megabytes * 1024 * 1024))
+	elapsed := time.Now().Sub(start)
+	diskWritten.Add(ctx, written)
+	diskKept.Add(ctx, written)
+	attributes := []attribute.KeyValue{
+		attribute.String("treactor.disk.mode", r.Mode),
+		attribute.Int64("treactor.disk.bytes", written),
+		attribute.Int64("treactor.disk.elapsed_ms", elapsed.Milliseconds()),
+	}
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Disk action failed after %d bytes: %s", written, err)
+		attributes = append(attributes, attribute.String("treactor.disk.error", err.Error()))
+	}
+	actionEvent(ctx, "disk", value, attributes...)
+	if name == "" {
+		return func() {}
+	}
+	return r.release(func() {
+		os.Remove(name)
+		diskKept.Add(context.Background(), -written)
+	})
+}
+
+// writeFile writes the size in chunks of a megabyte and fsyncs, it returns the bytes written and the file
+func writeFile(size int64) (int64, string, error) {
+	f, err := ioutil.TempFile(resource.DiskDir, "treactor-disk-*")
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	chunk := touch(make([]byte, 1024*1024))
+	var written int64
+	for written < size {
+		n := int64(len(chunk))
+		if size-written < n {
+			n = size - written
+		}
+		w, err := f.Write(chunk[:n])
+		written += int64(w)
+		if err != nil {
+			return written, f.Name(), err
+		}
+	}
+	return written, f.Name(), f.Sync()
+}
+
+// goroutines parks goroutines till the retention ends
+func goroutines(ctx context.Context, value string) func() {
+	count, r, err := parseRetention("goroutines", value)
+	if err == nil && count > maxGoroutines {
+		err = fmt.Errorf("goroutines needs at most %d goroutines", maxGoroutines)
+	}
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Ignoring goroutines action: %s", err)
+		return func() {}
+	}
+	n := int(count)
+	parked := make(chan struct{})
+	for i := 0; i < n; i++ {
+		go func() {
+			<-parked
+		}()
+	}
+	goroutinesHeld.Add(ctx, int64(n))
+	actionEvent(ctx, "goroutines", value,
+		attribute.String("treactor.goroutines.mode", r.Mode),
+		attribute.Int("treactor.goroutines.count", n))
+	return r.release(func() {
+		close(parked)
+		goroutinesHeld.Add(context.Background(), -int64(n))
+	})
+}
+
+// fd opens file descriptors and keeps them open till the retention ends, the action stops at the first error
+// (like too many open files)
+func fd(ctx context.Context, value string) func() {
+	count, r, err := parseRetention("fd", value)
+	if err == nil && count > maxFds {
+		err = fmt.Errorf("fd needs at most %d file descriptors", maxFds)
+	}
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Ignoring fd action: %s", err)
+		return func() {}
+	}
+	var files []*os.File
+	for i := 0; i < int(count); i++ {
+		f, err := os.Open(os.DevNull)
+		if err != nil {
+			resource.Logger.WarningF(ctx, "Fd action stopped after %d file descriptors: %s", len(files), err)
+			break
+		}
+		files = append(files, f)
+	}
+	fdsHeld.Add(ctx, int64(len(files)))
+	actionEvent(ctx, "fd", value,
+		attribute.String("treactor.fd.mode", r.Mode),
+		attribute.Int("treactor.fd.count", len(files)))
+	return r.release(func() {
+		for _, f := range files {
+			f.Close()
+		}
+		fdsHeld.Add(context.Background(), -int64(len(files)))
+	})
+}
+
+// sharedLock is contended by all the lock actions of the atom, also of concurrent requests
+var sharedLock sync.Mutex
+
+// lockSection is the number of pi terms computed while holding the shared lock
+const lockSection = 20000
+
+// lock lets goroutines contend on the shared lock for the duration, every one of them computes a bit of pi while
+// holding it. The waits show up in the block and mutex profiles.
+//
+//	lock:D                4 goroutines contend for D
+//	lock:D@W              W goroutines contend for D
+func lock(ctx context.Context, value string) {
+	spec := strings.SplitN(value, "@", 2)
+	duration, err := resource.ParseDuration(spec[0])
+	workers := 4
+	if err == nil && len(spec) == 2 {
+		workers, err = strconv.Atoi(spec[1])
+		if err == nil && (workers < 1 || workers > maxCores) {
+			err = fmt.Errorf("lock needs a number of goroutines between 1 and %d", maxCores)
+		}
+	}
+	if err != nil {
+		resource.Logger.WarningF(ctx, "Ignoring lock action: %s", err)
+		return
+	}
+	deadline := time.Now().Add(duration)
+	waits := make([]time.Duration, workers)
+	acquired := make([]int, workers)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) && ctx.Err() == nil {
+				start := time.Now()
+				sharedLock.Lock()
+				waits[i] += time.Now().Sub(start)
+				pi.Partial(0, lockSection)
+				sharedLock.Unlock()
+				acquired[i]++
+			}
+		}()
+	}
+	wg.Wait()
+	var wait time.Duration
+	total := 0
+	for i := range waits {
+		wait += waits[i]
+		total += acquired[i]
+	}
+	lockWait.Record(ctx, wait.Milliseconds())
+	actionEvent(ctx, "lock", value,
+		attribute.Int("treactor.lock.goroutines", workers),
+		attribute.Int("treactor.lock.acquired", total),
+		attribute.Int64("treactor.lock.wait_ms", wait.Milliseconds()))
+}
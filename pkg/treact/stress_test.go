@@ -0,0 +1,100 @@
+package treact
+
+import (
+	"context"
+	"io/ioutil"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/treactor/treactor-go/pkg/resource"
+)
+
+func TestParseRetention(t *testing.T) {
+	amount, r, err := parseRetention("fd", "100")
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, amount)
+	assert.Equal(t, retention{Mode: "request"}, r)
+
+	_, r, err = parseRetention("disk", "16@hold:2s")
+	assert.NoError(t, err)
+	assert.Equal(t, retention{Mode: "hold", Duration: 2 * time.Second}, r)
+
+	_, r, err = parseRetention("goroutines", "1000@leak")
+	assert.NoError(t, err)
+	assert.Equal(t, retention{Mode: "leak"}, r)
+
+	for _, in := range []string{"x", "-1", "10@hold", "10@forever"} {
+		_, _, err := parseRetention("fd", in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestDisk(t *testing.T) {
+	dir := resource.DiskDir
+	t.Cleanup(func() { resource.DiskDir = dir })
+	resource.DiskDir = t.TempDir()
+	release := disk(context.Background(), "1.5")
+	files, _ := ioutil.ReadDir(resource.DiskDir)
+	assert.Len(t, files, 1)
+	assert.Equal(t, int64(1536*1024), files[0].Size())
+	release()
+	files, _ = ioutil.ReadDir(resource.DiskDir)
+	assert.Len(t, files, 0)
+}
+
+func TestGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+	release := goroutines(context.Background(), "100")
+	assert.GreaterOrEqual(t, runtime.NumGoroutine(), before+100)
+	release()
+	assert.Eventually(t, func() bool {
+		return runtime.NumGoroutine() < before+100
+	}, time.Second, 10*time.Millisecond)
+}
+
+// openFds is the number of open file descriptors of the process
+func openFds(t *testing.T) int {
+	fds, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("no /proc/self/fd to count the file descriptors")
+	}
+	return len(fds)
+}
+
+func TestFd(t *testing.T) {
+	before := openFds(t)
+	release := fd(context.Background(), "10")
+	assert.GreaterOrEqual(t, openFds(t), before+10)
+	release()
+	assert.LessOrEqual(t, openFds(t), before)
+
+	// the hold starts when the atom answered, not when the descriptors were opened
+	release = fd(context.Background(), "10@hold:20ms")
+	time.Sleep(50 * time.Millisecond)
+	assert.GreaterOrEqual(t, openFds(t), before+10)
+	release()
+	assert.GreaterOrEqual(t, openFds(t), before+10)
+	assert.Eventually(t, func() bool {
+		return openFds(t) <= before
+	}, time.Second, 10*time.Millisecond)
+
+	_, r, _ := parseRetention("fd", "10@leak")
+	called := false
+	r.release(func() { called = true })()
+	assert.False(t, called)
+}
+
+func TestLock(t *testing.T) {
+	start := time.Now()
+	lock(context.Background(), "100ms@3")
+	assert.GreaterOrEqual(t, time.Since(start).Milliseconds(), int64(100))
+
+	// a cancelled request stops contending long before the duration passed
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	lock(ctx, "1m@3")
+	assert.Less(t, time.Since(start).Milliseconds(), int64(30*1000))
+}
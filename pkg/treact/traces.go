@@ -0,0 +1,116 @@
+package treact
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/treactor/treactor-go/pkg/resource"
+	"github.com/treactor/treactor-go/pkg/tracestore"
+)
+
+var waterfallTemplate = template.Must(template.New("waterfall").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Trace {{.TraceID}}</title>
+<style>
+body { font-family: sans-serif; font-size: 13px; margin: 20px; }
+table { border-collapse: collapse; width: 100%; }
+td { padding: 2px 4px; vertical-align: middle; white-space: nowrap; }
+tr:hover { background: #f0f0f0; }
+td.name { width: 35%; overflow: hidden; }
+td.duration { width: 8%; text-align: right; color: #555; }
+.track { position: relative; height: 14px; background: #fafafa; }
+.bar { position: absolute; height: 14px; min-width: 1px; background: #4285f4; }
+.bar.client { background: #34a853; }
+.bar.server { background: #fbbc05; }
+.bar.Error { background: #ea4335; }
+.service { color: #888; }
+details { white-space: normal; color: #555; }
+</style>
+</head>
+<body>
+<h3>Trace {{.TraceID}}</h3>
+<p>{{.Spans}} spans, {{.Duration}}</p>
+<table>
+{{range .Bars}}
+<tr>
+<td class="name" style="padding-left: {{.Indent}}px">
+<details><summary>{{.Name}} <span class="service">{{.Service}}</span></summary>
+{{.Kind}} {{.SpanID}}{{range $k, $v := .Attributes}}<br>{{$k}}={{$v}}{{end}}{{range .Events}}<br>event {{.Name}}{{range $k, $v := .Attributes}} {{$k}}={{$v}}{{end}}{{end}}
+</details>
+</td>
+<td class="duration">{{.Duration}}</td>
+<td><div class="track"><div class="bar {{.Kind}} {{.Status}}" style="left: {{.Offset}}%; width: {{.Width}}%"></div></div></td>
+</tr>
+{{end}}
+</table>
+</body>
+</html>
+`))
+
+type waterfallBar struct {
+	tracestore.Bar
+	Indent int
+}
+
+// TReactTracesHandle serves the in-memory trace store:
+//
+//	/treact/traces                 list of recent traces
+//	/treact/traces/{traceId}       span tree as JSON
+//	/treact/traces/{traceId}/view  span tree as HTML waterfall
+//
+// It is not instrumented, looking at a trace should not create new ones.
+func TReactTracesHandle(w http.ResponseWriter, r *http.Request) {
+	if resource.TraceStore == nil {
+		http.Error(w, "trace store disabled, set TREACTOR_TRACE_STORE", http.StatusNotFound)
+		return
+	}
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, resource.Base+"/traces"), "/")
+	if path == "" {
+		writeJson(w, resource.TraceStore.Summaries())
+		return
+	}
+	parts := strings.Split(path, "/")
+	spans, ok := resource.TraceStore.Spans(parts[0])
+	if !ok {
+		http.Error(w, "trace not found", http.StatusNotFound)
+		return
+	}
+	tree := tracestore.BuildTree(parts[0], spans)
+	if len(parts) > 1 && parts[1] == "view" {
+		writeWaterfall(w, tree)
+		return
+	}
+	writeJson(w, tree)
+}
+
+func writeJson(w http.ResponseWriter, v interface{}) {
+	bytes, _ := json.MarshalIndent(v, "", "\t")
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bytes)
+}
+
+func writeWaterfall(w http.ResponseWriter, tree *tracestore.Tree) {
+	bars := tree.Waterfall()
+	view := struct {
+		TraceID  string
+		Spans    int
+		Duration string
+		Bars     []waterfallBar
+	}{
+		TraceID:  tree.TraceID,
+		Spans:    tree.Spans,
+		Duration: tree.EndTime.Sub(tree.StartTime).String(),
+		Bars:     make([]waterfallBar, len(bars)),
+	}
+	for i, bar := range bars {
+		view.Bars[i] = waterfallBar{Bar: bar, Indent: 4 + bar.Depth*16}
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := waterfallTemplate.Execute(w, view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}